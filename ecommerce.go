@@ -1,6 +1,8 @@
 package ecommerce
 
 import (
+	"context"
+
 	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
 	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/repository"
 	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/service"
@@ -8,6 +10,21 @@ import (
 
 type Item = domain.Item
 type Customer = domain.Customer
+type ItemRef = domain.ItemRef
+
+var ErrInvalidItemRef = domain.ErrInvalidItemRef
+
+func ParseItemRef(value string) (ItemRef, error) {
+	return domain.ParseItemRef(value)
+}
+
+func ParseAddress(text string) ParsedAddress {
+	return domain.ParseAddress(text)
+}
+
+func NormalizePhoneE164(phone, defaultCountry string) (string, error) {
+	return domain.NormalizePhoneE164(phone, defaultCountry)
+}
 
 type EcommerceService = service.EcommerceService
 type EcommerceCredentialsService = service.EcommerceCredentialsService
@@ -15,6 +32,123 @@ type EcommerceCredentials = service.EcommerceCredentials
 type IntegrationService = service.IntegrationService
 type IntegrationResponse = service.IntegrationResponse
 type IntegrationConfigResponse = service.IntegrationConfigResponse
+type StockService = service.StockService
+type StockReservation = domain.StockReservation
+type StockReservationStore = repository.StockReservationStore
+type StockLedgerEntry = domain.StockLedgerEntry
+type StockLedgerStore = repository.StockLedgerStore
+type InsufficientStockError = service.InsufficientStockError
+type CustomerInput = domain.CustomerInput
+type Address = domain.Address
+type Order = domain.Order
+type OrderItem = domain.OrderItem
+type OrderQuery = domain.OrderQuery
+type OrderStatus = domain.OrderStatus
+type PaymentStatus = domain.PaymentStatus
+type ShippingStatus = domain.ShippingStatus
+type OrderTransitionError = domain.OrderTransitionError
+type OrderItemPriceOptions = domain.OrderItemPriceOptions
+type OrderNote = domain.OrderNote
+type AuctionNote = domain.AuctionNote
+type Shipment = domain.Shipment
+type ShipmentItem = domain.ShipmentItem
+type RefundOptions = domain.RefundOptions
+type RefundResult = domain.RefundResult
+type ShoppingCartItem = domain.ShoppingCartItem
+type CartItemInput = domain.CartItemInput
+type AddressKind = domain.AddressKind
+type CustomerUpdate = domain.CustomerUpdate
+type CustomerQuery = domain.CustomerQuery
+type CustomerRole = domain.CustomerRole
+type CustomerRegistration = domain.CustomerRegistration
+type DuplicateCandidate = domain.DuplicateCandidate
+type DuplicateReport = domain.DuplicateReport
+type OrderReassignment = domain.OrderReassignment
+type DuplicateCustomerOptions = service.DuplicateCustomerOptions
+type ParsedAddress = domain.ParsedAddress
+type Country = domain.Country
+type Product = domain.Product
+type ProductImage = domain.ProductImage
+type StateProvince = domain.StateProvince
+type ErasureMode = domain.ErasureMode
+type ErasureReport = domain.ErasureReport
+type OrderPersonalData = domain.OrderPersonalData
+type CustomerDataExport = domain.CustomerDataExport
+type AudienceMember = domain.AudienceMember
+type AudienceFormat = domain.AudienceFormat
+type AudienceExportOptions = service.AudienceExportOptions
+type NewsletterSubscription = domain.NewsletterSubscription
+type CartValidationError = domain.CartValidationError
+type CartValidationIssue = domain.CartValidationIssue
+type OrderOption = service.OrderOption
+type CheckoutService = service.CheckoutService
+type CheckoutRequest = domain.CheckoutRequest
+type CheckoutResult = domain.CheckoutResult
+type CheckoutLog = domain.CheckoutLog
+type CheckoutLogStore = repository.CheckoutLogStore
+type AuctionWinner = domain.AuctionWinner
+type AuctionLot = domain.AuctionLot
+type StockUpdate = service.StockUpdate
+type StockBatchOptions = service.StockBatchOptions
+type StockUpdateResult = service.StockUpdateResult
+type StockBatchReport = service.StockBatchReport
+
+const (
+	CustomerRegistrationAny        = domain.CustomerRegistrationAny
+	CustomerRegistrationGuest      = domain.CustomerRegistrationGuest
+	CustomerRegistrationRegistered = domain.CustomerRegistrationRegistered
+)
+
+const (
+	AudienceFormatCSV       = domain.AudienceFormatCSV
+	AudienceFormatJSONLines = domain.AudienceFormatJSONLines
+)
+
+const (
+	ErasureAnonymize = domain.ErasureAnonymize
+	ErasureDelete    = domain.ErasureDelete
+)
+
+const (
+	AddressKindBilling  = domain.AddressKindBilling
+	AddressKindShipping = domain.AddressKindShipping
+)
+
+const (
+	ShoppingCartTypeCart     = domain.ShoppingCartTypeCart
+	ShoppingCartTypeWishlist = domain.ShoppingCartTypeWishlist
+)
+
+const (
+	OrderTotalsExplicit = domain.OrderTotalsExplicit
+	OrderTotalsServer   = domain.OrderTotalsServer
+)
+
+const (
+	StockBatchBestEffort       = service.StockBatchBestEffort
+	StockBatchStopOnFirstError = service.StockBatchStopOnFirstError
+)
+
+var (
+	ErrInsufficientStock      = service.ErrInsufficientStock
+	ErrStockConflict          = service.ErrStockConflict
+	ErrReservationNotFound    = service.ErrReservationNotFound
+	ErrReservationReleased    = service.ErrReservationReleased
+	ErrReservationCommitted   = service.ErrReservationCommitted
+	ErrStockBatchAborted      = service.ErrStockBatchAborted
+	ErrCheckoutFailed         = service.ErrCheckoutFailed
+	ErrCustomerNotFound       = domain.ErrCustomerNotFound
+	ErrCustomerEmailTaken     = domain.ErrCustomerEmailTaken
+	ErrInvalidPhone           = domain.ErrInvalidPhone
+	ErrCountryNotFound        = domain.ErrCountryNotFound
+	ErrStateNotFound          = domain.ErrStateNotFound
+	ErrIllegalOrderTransition = domain.ErrIllegalOrderTransition
+	ErrRefundNotSupported     = domain.ErrRefundNotSupported
+	ErrInvalidRefundAmount    = domain.ErrInvalidRefundAmount
+	ErrOrderNotRefundable     = domain.ErrOrderNotRefundable
+	ErrOrderTotalsMismatch    = domain.ErrOrderTotalsMismatch
+	ErrCartValidation         = domain.ErrCartValidation
+)
 
 func NewEcommerceService() EcommerceService {
 	repo := repository.NewEcommerceRepository()
@@ -25,3 +159,33 @@ func NewEcommerceCredentialsService(integrationService IntegrationService) Ecomm
 	ecommerceService := NewEcommerceService()
 	return service.NewEcommerceCredentialsService(integrationService, ecommerceService)
 }
+
+func NewStockService() StockService {
+	return NewStockServiceWithStores(repository.NewInMemoryStockReservationStore(), repository.NewInMemoryStockLedgerStore())
+}
+
+func NewStockServiceWithStores(reservations StockReservationStore, ledger StockLedgerStore) StockService {
+	repo := repository.NewEcommerceRepository()
+	return service.NewStockService(repo, reservations, ledger)
+}
+
+func NewCheckoutService() CheckoutService {
+	return NewCheckoutServiceWithStore(repository.NewInMemoryCheckoutLogStore())
+}
+
+func NewCheckoutServiceWithStore(logs CheckoutLogStore) CheckoutService {
+	repo := repository.NewEcommerceRepository()
+	return service.NewCheckoutService(repo, logs)
+}
+
+func WithIdempotencyKey(key string) OrderOption {
+	return service.WithIdempotencyKey(key)
+}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return service.WithActor(ctx, actor)
+}
+
+func WithDefaultCountry(ctx context.Context, countryISO string) context.Context {
+	return service.WithDefaultCountry(ctx, countryISO)
+}
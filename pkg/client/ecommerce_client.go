@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -15,7 +17,9 @@ type EcommerceClient interface {
 	GetItems(baseUrl, apiKey string, page, limit int, publishedStatus bool, filters map[string]string) ([]byte, error)
 	GetItemByID(baseUrl, apiKey, itemId string) ([]byte, error)
 	GetCustomers(baseUrl, apiKey string) ([]byte, error)
-	GetAllCustomers(baseUrl, apiKey string) ([]byte, error)
+	GetAllCustomers(baseUrl, apiKey string, roleID int) ([]byte, error)
+	ListCustomers(baseUrl, apiKey string, params url.Values) ([]byte, error)
+	GetCustomerRoles(baseUrl, apiKey string) ([]byte, error)
 	GetCustomerByID(baseUrl, apiKey, id string) ([]byte, error)
 	GetOrders(baseUrl, apiKey string) ([]byte, error)
 	GetAllOrders(baseUrl, apiKey string) ([]byte, error)
@@ -31,6 +35,29 @@ type EcommerceClient interface {
 	UpdateOrderItemPrice(baseUrl, apiKey string, orderID, itemID int, orderItemData []byte) error
 	UpdateOrder(baseUrl, apiKey string, orderID int, orderData []byte) error
 	GetOrderByID(baseUrl, apiKey string, orderID int) ([]byte, error)
+	UpdateItemStock(baseUrl, apiKey, productID string, newStock int64) error
+	DeleteCustomer(baseUrl, apiKey string, customerID int) error
+	DeleteOrder(baseUrl, apiKey string, orderID int) error
+	GetCustomerOrders(baseUrl, apiKey string, customerID int) ([]byte, error)
+	SearchCustomersByEmail(baseUrl, apiKey, email string, limit int) ([]byte, error)
+	ListOrders(baseUrl, apiKey string, params url.Values) ([]byte, error)
+	RefundOrder(baseUrl, apiKey string, orderID int, refundData []byte) ([]byte, error)
+	CreateShipment(baseUrl, apiKey string, orderID int, shipmentData []byte) ([]byte, error)
+	GetOrderShipments(baseUrl, apiKey string, orderID int) ([]byte, error)
+	UpdateShipment(baseUrl, apiKey string, shipmentID int, shipmentData []byte) error
+	CreateOrderNote(baseUrl, apiKey string, orderID int, noteData []byte) ([]byte, error)
+	GetOrderNotes(baseUrl, apiKey string, orderID int) ([]byte, error)
+	GetShoppingCartItems(baseUrl, apiKey string, customerID int, cartType string) ([]byte, error)
+	UpdateShoppingCartItem(baseUrl, apiKey string, cartItemID int, cartItemData []byte) ([]byte, error)
+	DeleteShoppingCartItem(baseUrl, apiKey string, cartItemID int) error
+	ClearShoppingCart(baseUrl, apiKey string, customerID int, cartType string) error
+	UpdateCustomer(baseUrl, apiKey string, customerID int, customerData []byte) error
+	GetCustomerAddresses(baseUrl, apiKey string, customerID int) ([]byte, error)
+	UpdateCustomerAddress(baseUrl, apiKey string, customerID, addressID int, addressData []byte) ([]byte, error)
+	DeleteCustomerAddress(baseUrl, apiKey string, customerID, addressID int) error
+	GetNewsletterSubscriptions(baseUrl, apiKey string, page, limit int) ([]byte, error)
+	GetCountries(baseUrl, apiKey string) ([]byte, error)
+	GetStates(baseUrl, apiKey string, countryID int) ([]byte, error)
 }
 
 type ecommerceClient struct {
@@ -195,15 +222,18 @@ func (c *ecommerceClient) GetCustomers(baseUrl, apiKey string) ([]byte, error) {
 	return ioutil.ReadAll(resp.Body)
 }
 
-func (c *ecommerceClient) GetAllCustomers(baseUrl, apiKey string) ([]byte, error) {
+func (c *ecommerceClient) GetAllCustomers(baseUrl, apiKey string, roleID int) ([]byte, error) {
 	var allCustomers []map[string]interface{}
 	page := 1
 	limit := 100
 
-	fmt.Printf("[GET_ALL_CUSTOMERS] Iniciando obtención de todos los clientes con paginación (RoleId=3)\n")
+	fmt.Printf("[GET_ALL_CUSTOMERS] Iniciando obtención de todos los clientes con paginación (RoleId=%d)\n", roleID)
 
 	for {
-		url := fmt.Sprintf("%s/api/customers?Page=%d&Limit=%d&RoleId=3", baseUrl, page, limit)
+		url := fmt.Sprintf("%s/api/customers?Page=%d&Limit=%d", baseUrl, page, limit)
+		if roleID != 0 {
+			url += fmt.Sprintf("&RoleId=%d", roleID)
+		}
 		fmt.Printf("[GET_ALL_CUSTOMERS] Obteniendo página %d (limit: %d)\n", page, limit)
 
 		req, err := http.NewRequest("GET", url, nil)
@@ -236,7 +266,7 @@ func (c *ecommerceClient) GetAllCustomers(baseUrl, apiKey string) ([]byte, error
 		}
 
 		customersInPage := len(response.Customers)
-		fmt.Printf("[GET_ALL_CUSTOMERS] Página %d: se obtuvieron %d clientes (RoleId=3)\n", page, customersInPage)
+		fmt.Printf("[GET_ALL_CUSTOMERS] Página %d: se obtuvieron %d clientes (RoleId=%d)\n", page, customersInPage, roleID)
 
 		if customersInPage == 0 {
 			fmt.Printf("[GET_ALL_CUSTOMERS] No hay más clientes, terminando paginación\n")
@@ -263,7 +293,7 @@ func (c *ecommerceClient) GetAllCustomers(baseUrl, apiKey string) ([]byte, error
 }
 
 func (c *ecommerceClient) GetCustomerByID(baseUrl, apiKey, id string) ([]byte, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/customers/%s", baseUrl, id), nil)
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/customers/%s", baseUrl, id), nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
@@ -744,3 +774,263 @@ func (c *ecommerceClient) GetOrderByID(baseUrl, apiKey string, orderID int) ([]b
 
 	return ioutil.ReadAll(resp.Body)
 }
+
+func (c *ecommerceClient) UpdateItemStock(baseUrl, apiKey, productID string, newStock int64) error {
+	url := fmt.Sprintf("%s/api/products/%s", baseUrl, productID)
+	fmt.Printf("[HTTP] PUT %s\n", url)
+
+	payload := map[string]interface{}{
+		"product": map[string]interface{}{
+			"stock_quantity": newStock,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		fmt.Printf("[HTTP] ERROR: Failed to create request: %v\n", err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		fmt.Printf("[HTTP] ERROR: Failed to send request: %v\n", err)
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	fmt.Printf("[HTTP] Response Status: %d\n", resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		bodyBytes, _ := ioutil.ReadAll(resp.Body)
+		fmt.Printf("[HTTP] ERROR: Unexpected status code: %d, Body: %s\n", resp.StatusCode, string(bodyBytes))
+		return fmt.Errorf("failed to update stock, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *ecommerceClient) DeleteCustomer(baseUrl, apiKey string, customerID int) error {
+	url := fmt.Sprintf("%s/api/customers/%d", baseUrl, customerID)
+	_, err := c.send("DELETE", url, apiKey, nil, "delete customer", http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+func (c *ecommerceClient) DeleteOrder(baseUrl, apiKey string, orderID int) error {
+	url := fmt.Sprintf("%s/api/orders/%d", baseUrl, orderID)
+	_, err := c.send("DELETE", url, apiKey, nil, "delete order", http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+func (c *ecommerceClient) GetCustomerOrders(baseUrl, apiKey string, customerID int) ([]byte, error) {
+	var allOrders []json.RawMessage
+	page := 1
+	limit := 100
+
+	for {
+		params := url.Values{}
+		params.Set("CustomerId", strconv.Itoa(customerID))
+		params.Set("Page", strconv.Itoa(page))
+		params.Set("Limit", strconv.Itoa(limit))
+
+		bodyBytes, err := c.ListOrders(baseUrl, apiKey, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			Orders []json.RawMessage `json:"orders"`
+		}
+		if err := json.Unmarshal(bodyBytes, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		allOrders = append(allOrders, response.Orders...)
+
+		if len(response.Orders) < limit {
+			break
+		}
+
+		page++
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"orders": allOrders,
+	})
+}
+
+func (c *ecommerceClient) ListOrders(baseUrl, apiKey string, params url.Values) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/orders?%s", baseUrl, params.Encode())
+	return c.send("GET", url, apiKey, nil, "list orders", http.StatusOK)
+}
+
+func (c *ecommerceClient) SearchCustomersByEmail(baseUrl, apiKey, email string, limit int) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/customers?Email=%s&Page=1&Limit=%d", baseUrl, url.QueryEscape(email), limit)
+	return c.send("GET", url, apiKey, nil, "search customers", http.StatusOK)
+}
+
+func (c *ecommerceClient) RefundOrder(baseUrl, apiKey string, orderID int, refundData []byte) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/orders/%d/refund", baseUrl, orderID)
+	return c.send("POST", url, apiKey, refundData, "refund order", http.StatusOK, http.StatusCreated, http.StatusNoContent)
+}
+
+func (c *ecommerceClient) CreateShipment(baseUrl, apiKey string, orderID int, shipmentData []byte) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/orders/%d/shipments", baseUrl, orderID)
+	return c.send("POST", url, apiKey, shipmentData, "create shipment", http.StatusOK, http.StatusCreated)
+}
+
+func (c *ecommerceClient) GetOrderShipments(baseUrl, apiKey string, orderID int) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/orders/%d/shipments", baseUrl, orderID)
+	return c.send("GET", url, apiKey, nil, "get order shipments", http.StatusOK)
+}
+
+func (c *ecommerceClient) UpdateShipment(baseUrl, apiKey string, shipmentID int, shipmentData []byte) error {
+	url := fmt.Sprintf("%s/api/shipments/%d", baseUrl, shipmentID)
+	_, err := c.send("PUT", url, apiKey, shipmentData, "update shipment", http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+func (c *ecommerceClient) CreateOrderNote(baseUrl, apiKey string, orderID int, noteData []byte) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/orders/%d/notes", baseUrl, orderID)
+	return c.send("POST", url, apiKey, noteData, "create order note", http.StatusOK, http.StatusCreated)
+}
+
+func (c *ecommerceClient) GetOrderNotes(baseUrl, apiKey string, orderID int) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/orders/%d/notes", baseUrl, orderID)
+	return c.send("GET", url, apiKey, nil, "get order notes", http.StatusOK)
+}
+
+func (c *ecommerceClient) GetShoppingCartItems(baseUrl, apiKey string, customerID int, cartType string) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/shopping_cart_items?ShoppingCartType=%s&CustomerId=%d", baseUrl, url.QueryEscape(cartType), customerID)
+	return c.send("GET", url, apiKey, nil, "get shopping cart items", http.StatusOK)
+}
+
+func (c *ecommerceClient) UpdateShoppingCartItem(baseUrl, apiKey string, cartItemID int, cartItemData []byte) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/shopping_cart_items/%d", baseUrl, cartItemID)
+	return c.send("PUT", url, apiKey, cartItemData, "update shopping cart item", http.StatusOK, http.StatusNoContent)
+}
+
+func (c *ecommerceClient) DeleteShoppingCartItem(baseUrl, apiKey string, cartItemID int) error {
+	url := fmt.Sprintf("%s/api/shopping_cart_items/%d", baseUrl, cartItemID)
+	_, err := c.send("DELETE", url, apiKey, nil, "delete shopping cart item", http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+func (c *ecommerceClient) ClearShoppingCart(baseUrl, apiKey string, customerID int, cartType string) error {
+	url := fmt.Sprintf("%s/api/shopping_cart_items?ShoppingCartType=%s&CustomerId=%d", baseUrl, url.QueryEscape(cartType), customerID)
+	_, err := c.send("DELETE", url, apiKey, nil, "clear shopping cart", http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+func (c *ecommerceClient) UpdateCustomer(baseUrl, apiKey string, customerID int, customerData []byte) error {
+	url := fmt.Sprintf("%s/api/customers/%d", baseUrl, customerID)
+	_, err := c.send("PUT", url, apiKey, customerData, "update customer", http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+func (c *ecommerceClient) GetCustomerAddresses(baseUrl, apiKey string, customerID int) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/customers/%d/addresses", baseUrl, customerID)
+	return c.send("GET", url, apiKey, nil, "get customer addresses", http.StatusOK)
+}
+
+func (c *ecommerceClient) UpdateCustomerAddress(baseUrl, apiKey string, customerID, addressID int, addressData []byte) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/customers/%d/addresses/%d", baseUrl, customerID, addressID)
+	return c.send("PUT", url, apiKey, addressData, "update customer address", http.StatusOK, http.StatusNoContent)
+}
+
+func (c *ecommerceClient) DeleteCustomerAddress(baseUrl, apiKey string, customerID, addressID int) error {
+	url := fmt.Sprintf("%s/api/customers/%d/addresses/%d", baseUrl, customerID, addressID)
+	_, err := c.send("DELETE", url, apiKey, nil, "delete customer address", http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+func (c *ecommerceClient) GetNewsletterSubscriptions(baseUrl, apiKey string, page, limit int) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/news_letter_subscriptions?Page=%d&Limit=%d", baseUrl, page, limit)
+	return c.send("GET", url, apiKey, nil, "get newsletter subscriptions", http.StatusOK)
+}
+
+func (c *ecommerceClient) ListCustomers(baseUrl, apiKey string, params url.Values) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/customers?%s", baseUrl, params.Encode())
+	return c.send("GET", url, apiKey, nil, "list customers", http.StatusOK)
+}
+
+func (c *ecommerceClient) GetCustomerRoles(baseUrl, apiKey string) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/customer_roles", baseUrl)
+	return c.send("GET", url, apiKey, nil, "get customer roles", http.StatusOK)
+}
+
+func (c *ecommerceClient) GetCountries(baseUrl, apiKey string) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/countries", baseUrl)
+	return c.send("GET", url, apiKey, nil, "get countries", http.StatusOK)
+}
+
+func (c *ecommerceClient) GetStates(baseUrl, apiKey string, countryID int) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/countries/%d/states", baseUrl, countryID)
+	return c.send("GET", url, apiKey, nil, "get states", http.StatusOK)
+}
+
+// send performs an authenticated JSON request and returns the response body
+// when the status code is one of okStatuses.
+func (c *ecommerceClient) send(method, url, apiKey string, payload []byte, action string, okStatuses ...int) ([]byte, error) {
+	fmt.Printf("[HTTP] %s %s\n", method, url)
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewBuffer(payload)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		fmt.Printf("[HTTP] ERROR: Failed to create request: %v\n", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		fmt.Printf("[HTTP] ERROR: Failed to send request: %v\n", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	fmt.Printf("[HTTP] Response Status: %d\n", resp.StatusCode)
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	for _, status := range okStatuses {
+		if resp.StatusCode == status {
+			return respBody, nil
+		}
+	}
+
+	fmt.Printf("[HTTP] ERROR: Unexpected status code: %d, Body: %s\n", resp.StatusCode, string(respBody))
+	return nil, &StatusError{Action: action, StatusCode: resp.StatusCode, Body: string(respBody)}
+}
+
+// StatusError is returned when the API answers with an unexpected status code.
+type StatusError struct {
+	Action     string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed to %s, status code: %d", e.Action, e.StatusCode)
+}
+
+// IsNotSupported reports whether the status code means the store does not
+// expose the endpoint at all.
+func (e *StatusError) IsNotSupported() bool {
+	return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusMethodNotAllowed || e.StatusCode == http.StatusNotImplemented
+}
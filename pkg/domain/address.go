@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Address struct {
+	ID              int    `json:"id,omitempty"`
+	FirstName       string `json:"first_name,omitempty"`
+	LastName        string `json:"last_name,omitempty"`
+	Email           string `json:"email,omitempty"`
+	Company         string `json:"company,omitempty"`
+	CountryID       int    `json:"country_id,omitempty"`
+	StateProvinceID int    `json:"state_province_id,omitempty"`
+	City            string `json:"city,omitempty"`
+	Address1        string `json:"address1,omitempty"`
+	Address2        string `json:"address2,omitempty"`
+	ZipPostalCode   string `json:"zip_postal_code,omitempty"`
+	PhoneNumber     string `json:"phone_number,omitempty"`
+	// Country and StateProvince accept an ISO code or a name; they are resolved
+	// to CountryID and StateProvinceID before the address is sent.
+	Country       string `json:"country,omitempty"`
+	StateProvince string `json:"province,omitempty"`
+}
+
+type AddressKind string
+
+const (
+	AddressKindBilling  AddressKind = "billing"
+	AddressKindShipping AddressKind = "shipping"
+)
+
+func (k AddressKind) Validate() error {
+	switch k {
+	case AddressKindBilling, AddressKindShipping:
+		return nil
+	default:
+		return fmt.Errorf("invalid address kind %q", k)
+	}
+}
+
+// SameLocation reports whether both addresses describe the same recipient and
+// location, ignoring IDs, case and surrounding whitespace.
+func (a Address) SameLocation(other Address) bool {
+	return sameText(a.FirstName, other.FirstName) &&
+		sameText(a.LastName, other.LastName) &&
+		sameText(a.Address1, other.Address1) &&
+		sameText(a.Address2, other.Address2) &&
+		sameText(a.City, other.City) &&
+		sameText(a.ZipPostalCode, other.ZipPostalCode) &&
+		a.CountryID == other.CountryID &&
+		a.StateProvinceID == other.StateProvinceID
+}
+
+func FindSameLocation(addresses []Address, target Address) (*Address, bool) {
+	for i := range addresses {
+		if addresses[i].SameLocation(target) {
+			return &addresses[i], true
+		}
+	}
+	return nil, false
+}
+
+func sameText(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+var zipPattern = regexp.MustCompile(`(?i)\b(?:c\.?\s?p\.?\s*)?(\d{4,6}(?:-\d{4})?)\b`)
+
+// countryNames maps common country names, in Spanish and English, to ISO
+// alpha-2 codes.
+var countryNames = map[string]string{
+	"mexico": "MX", "méxico": "MX", "estados unidos": "US", "united states": "US", "usa": "US", "eeuu": "US",
+	"canada": "CA", "canadá": "CA", "españa": "ES", "espana": "ES", "spain": "ES", "argentina": "AR",
+	"colombia": "CO", "chile": "CL", "peru": "PE", "perú": "PE", "brasil": "BR", "brazil": "BR",
+	"guatemala": "GT", "costa rica": "CR", "panama": "PA", "panamá": "PA", "uruguay": "UY",
+	"paraguay": "PY", "ecuador": "EC", "bolivia": "BO", "venezuela": "VE", "portugal": "PT",
+}
+
+// ParsedAddress is a free-text address split into its parts. Country holds the
+// ISO alpha-2 code when it could be recognized.
+type ParsedAddress struct {
+	Street        string
+	City          string
+	State         string
+	ZipPostalCode string
+	Country       string
+}
+
+// ParseAddress splits a free-text address written as comma or line separated
+// parts, e.g. "Av. Juárez 123, Col. Centro, Guadalajara, Jalisco, C.P. 44100,
+// México". The street comes first, followed by the city and the state; the zip
+// code and country are recognized wherever they appear.
+func ParseAddress(text string) ParsedAddress {
+	var parts []string
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' || r == ';' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	var parsed ParsedAddress
+	if n := len(parts); n > 1 {
+		if iso, ok := CountryISOCode(parts[n-1]); ok {
+			parsed.Country = iso
+			parts = parts[:n-1]
+		}
+	}
+
+	for i := len(parts) - 1; i > 0 && parsed.ZipPostalCode == ""; i-- {
+		match := zipPattern.FindStringSubmatchIndex(parts[i])
+		if match == nil {
+			continue
+		}
+		parsed.ZipPostalCode = parts[i][match[2]:match[3]]
+		rest := strings.TrimSpace(parts[i][:match[0]] + " " + parts[i][match[1]:])
+		if rest == "" {
+			parts = append(parts[:i], parts[i+1:]...)
+		} else {
+			parts[i] = rest
+		}
+	}
+
+	switch n := len(parts); {
+	case n >= 3:
+		parsed.Street = strings.Join(parts[:n-2], ", ")
+		parsed.City = parts[n-2]
+		parsed.State = parts[n-1]
+	case n == 2:
+		parsed.Street = parts[0]
+		parsed.City = parts[1]
+	case n == 1:
+		parsed.Street = parts[0]
+	}
+	return parsed
+}
+
+// CountryISOCode recognizes an ISO alpha-2 code or a common country name.
+func CountryISOCode(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if _, ok := CountryCallingCode(value); ok && len(value) == 2 {
+		return strings.ToUpper(value), true
+	}
+	iso, ok := countryNames[strings.ToLower(strings.Trim(value, "."))]
+	return iso, ok
+}
+
+// Label formats the address on one line for shipping labels and matching.
+func (p ParsedAddress) Label() string {
+	var parts []string
+	for _, part := range []string{p.Street, p.City, p.State, p.ZipPostalCode, p.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ApplyTo fills the empty street, city, zip, state and country fields of the
+// address. State and country are set as text, to be resolved to remote IDs.
+func (p ParsedAddress) ApplyTo(address *Address) {
+	if address.City == "" {
+		address.Address1 = p.Street
+		address.City = p.City
+	}
+	if address.ZipPostalCode == "" {
+		address.ZipPostalCode = p.ZipPostalCode
+	}
+	if address.StateProvinceID == 0 && address.StateProvince == "" {
+		address.StateProvince = p.State
+	}
+	if address.CountryID == 0 && address.Country == "" {
+		address.Country = p.Country
+	}
+}
+
+func (c *Customer) ParsedAddress() ParsedAddress {
+	return ParseAddress(c.Address)
+}
+
+func (c *Customer) NormalizedPhone(defaultCountry string) (string, error) {
+	return NormalizePhoneE164(c.Phone, defaultCountry)
+}
+
+// Normalized trims the address fields, formats the phone as E.164 when
+// possible and splits a free-text street line that also holds the city and zip
+// code. An unparseable phone is kept as entered.
+func (a Address) Normalized(defaultCountry string) Address {
+	for _, field := range []*string{&a.FirstName, &a.LastName, &a.Company, &a.City, &a.Address1, &a.Address2, &a.ZipPostalCode, &a.PhoneNumber} {
+		*field = strings.TrimSpace(*field)
+	}
+	a.Email = NormalizeEmail(a.Email)
+
+	if phone, err := NormalizePhoneE164(a.PhoneNumber, defaultCountry); err == nil {
+		a.PhoneNumber = phone
+	}
+	if a.City == "" && strings.Contains(a.Address1, ",") {
+		ParseAddress(a.Address1).ApplyTo(&a)
+	}
+	return a
+}
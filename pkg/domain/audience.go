@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+type NewsletterSubscription struct {
+	ID           int        `json:"id,omitempty"`
+	Email        string     `json:"email"`
+	Active       bool       `json:"active"`
+	StoreID      int        `json:"store_id,omitempty"`
+	CreatedOnUtc *time.Time `json:"created_on_utc,omitempty"`
+}
+
+type ConsentStatus string
+
+const (
+	ConsentSubscribed   ConsentStatus = "subscribed"
+	ConsentUnsubscribed ConsentStatus = "unsubscribed"
+	ConsentUnknown      ConsentStatus = "unknown"
+)
+
+const (
+	AudienceSourceCustomer = "customer"
+	AudienceSourceOrder    = "order"
+)
+
+type AudienceMember struct {
+	Email         string        `json:"email"`
+	CustomerID    int           `json:"customer_id,omitempty"`
+	Name          string        `json:"name,omitempty"`
+	Sources       []string      `json:"sources"`
+	Consent       ConsentStatus `json:"consent"`
+	OrderCount    int           `json:"order_count"`
+	LastOrderDate *time.Time    `json:"last_order_date,omitempty"`
+	TotalSpend    float64       `json:"total_spend"`
+}
+
+// BuildAudience merges customers and order billing emails into one member per
+// normalized email. Each order counts towards its billing email, or towards the
+// customer's email when the order has no billing email. Cancelled orders are
+// ignored for spend, and refunds are subtracted.
+func BuildAudience(customers []Customer, orders []Order, subscriptions []NewsletterSubscription) []AudienceMember {
+	builder := NewAudienceBuilder(orders, subscriptions)
+	audience := append(builder.AddCustomers(customers), builder.Remaining()...)
+	sortAudience(audience)
+	return audience
+}
+
+// AudienceBuilder assembles the audience incrementally. Orders and
+// subscriptions are indexed up front and customers are added page by page, so
+// each member can be emitted as soon as its customer is seen. A member is
+// emitted once; a later customer with the same email adds nothing to it.
+type AudienceBuilder struct {
+	byEmail    map[string]*AudienceMember
+	byCustomer map[int]*AudienceMember
+	consent    map[string]ConsentStatus
+	emitted    map[string]bool
+}
+
+func NewAudienceBuilder(orders []Order, subscriptions []NewsletterSubscription) *AudienceBuilder {
+	b := &AudienceBuilder{
+		byEmail:    make(map[string]*AudienceMember),
+		byCustomer: make(map[int]*AudienceMember),
+		consent:    make(map[string]ConsentStatus),
+		emitted:    make(map[string]bool),
+	}
+
+	for i := range orders {
+		order := &orders[i]
+		var stats *AudienceMember
+		email := ""
+		if order.BillingAddress != nil {
+			email = NormalizeEmail(order.BillingAddress.Email)
+		}
+		if email != "" {
+			if stats = b.byEmail[email]; stats == nil {
+				stats = &AudienceMember{Email: email, CustomerID: order.CustomerID}
+				b.byEmail[email] = stats
+			}
+		} else if order.CustomerID > 0 {
+			if stats = b.byCustomer[order.CustomerID]; stats == nil {
+				stats = &AudienceMember{CustomerID: order.CustomerID}
+				b.byCustomer[order.CustomerID] = stats
+			}
+		} else {
+			continue
+		}
+		stats.addOrder(order)
+	}
+
+	for _, subscription := range subscriptions {
+		email := NormalizeEmail(subscription.Email)
+		if subscription.Active {
+			b.consent[email] = ConsentSubscribed
+		} else if b.consent[email] != ConsentSubscribed {
+			b.consent[email] = ConsentUnsubscribed
+		}
+	}
+	return b
+}
+
+// AddCustomers returns the members for customers whose email has not been
+// emitted yet, with the stats of their orders.
+func (b *AudienceBuilder) AddCustomers(customers []Customer) []AudienceMember {
+	var members []AudienceMember
+	for _, customer := range customers {
+		email := NormalizeEmail(customer.Email)
+		if email == "" || b.emitted[email] {
+			continue
+		}
+
+		m := AudienceMember{Email: email, CustomerID: customer.ID, Name: customer.Name, Sources: []string{AudienceSourceCustomer}}
+		for _, stats := range []*AudienceMember{b.byEmail[email], b.byCustomer[customer.ID]} {
+			if stats != nil {
+				m.mergeOrders(stats)
+			}
+		}
+		members = append(members, b.emit(m))
+	}
+	return members
+}
+
+// Remaining returns the members that only appear as order billing emails. It
+// is meant to be called once, after every customer has been added.
+func (b *AudienceBuilder) Remaining() []AudienceMember {
+	var members []AudienceMember
+	for email, stats := range b.byEmail {
+		if b.emitted[email] {
+			continue
+		}
+		m := AudienceMember{Email: email, CustomerID: stats.CustomerID}
+		m.mergeOrders(stats)
+		members = append(members, b.emit(m))
+	}
+	sortAudience(members)
+	return members
+}
+
+func (b *AudienceBuilder) emit(m AudienceMember) AudienceMember {
+	b.emitted[m.Email] = true
+	m.Consent = ConsentUnknown
+	if consent, ok := b.consent[m.Email]; ok {
+		m.Consent = consent
+	}
+	return m
+}
+
+func (m *AudienceMember) addOrder(order *Order) {
+	m.Sources = []string{AudienceSourceOrder}
+	if order.OrderStatus == OrderStatusCancelled {
+		return
+	}
+	m.OrderCount++
+	m.TotalSpend = RoundPrice(m.TotalSpend + order.OrderTotal - order.RefundedAmount)
+	if order.CreatedOnUtc != nil && (m.LastOrderDate == nil || order.CreatedOnUtc.After(*m.LastOrderDate)) {
+		createdOn := *order.CreatedOnUtc
+		m.LastOrderDate = &createdOn
+	}
+}
+
+func (m *AudienceMember) mergeOrders(stats *AudienceMember) {
+	if !containsString(m.Sources, AudienceSourceOrder) {
+		m.Sources = append(m.Sources, AudienceSourceOrder)
+	}
+	m.OrderCount += stats.OrderCount
+	m.TotalSpend = RoundPrice(m.TotalSpend + stats.TotalSpend)
+	if stats.LastOrderDate != nil && (m.LastOrderDate == nil || stats.LastOrderDate.After(*m.LastOrderDate)) {
+		m.LastOrderDate = stats.LastOrderDate
+	}
+}
+
+func sortAudience(audience []AudienceMember) {
+	sort.Slice(audience, func(i, j int) bool {
+		return audience[i].Email < audience[j].Email
+	})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
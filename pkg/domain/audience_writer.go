@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AudienceFormat string
+
+const (
+	AudienceFormatCSV       AudienceFormat = "csv"
+	AudienceFormatJSONLines AudienceFormat = "jsonl"
+)
+
+var audienceCSVHeader = []string{"email", "customer_id", "name", "sources", "consent", "order_count", "last_order_date", "total_spend"}
+
+// AudienceWriter streams audience members as CSV or JSON Lines, one member per
+// row, so campaign tooling can start reading before the export finishes.
+type AudienceWriter struct {
+	format AudienceFormat
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+func NewAudienceWriter(w io.Writer, format AudienceFormat) (*AudienceWriter, error) {
+	switch format {
+	case AudienceFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(audienceCSVHeader); err != nil {
+			return nil, err
+		}
+		return &AudienceWriter{format: format, csv: writer}, nil
+	case AudienceFormatJSONLines:
+		return &AudienceWriter{format: format, json: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported audience format %q", format)
+	}
+}
+
+func (w *AudienceWriter) Write(member AudienceMember) error {
+	if w.json != nil {
+		return w.json.Encode(member)
+	}
+
+	lastOrderDate := ""
+	if member.LastOrderDate != nil {
+		lastOrderDate = member.LastOrderDate.UTC().Format(time.RFC3339)
+	}
+	customerID := ""
+	if member.CustomerID != 0 {
+		customerID = strconv.Itoa(member.CustomerID)
+	}
+
+	if err := w.csv.Write([]string{
+		member.Email,
+		customerID,
+		member.Name,
+		strings.Join(member.Sources, "|"),
+		string(member.Consent),
+		strconv.Itoa(member.OrderCount),
+		lastOrderDate,
+		strconv.FormatFloat(member.TotalSpend, 'f', 2, 64),
+	}); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
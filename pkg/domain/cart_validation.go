@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrCartValidation = errors.New("cart item validation failed")
+
+const (
+	CartIssueNotPublished       = "not_published"
+	CartIssueInsufficientStock  = "insufficient_stock"
+	CartIssueBelowMinimum       = "below_minimum_quantity"
+	CartIssueAboveMaximum       = "above_maximum_quantity"
+	CartIssueQuantityNotAllowed = "quantity_not_allowed"
+)
+
+type CartValidationIssue struct {
+	Code    string
+	Field   string
+	Message string
+}
+
+type CartValidationError struct {
+	ItemRef  ItemRef
+	Quantity int
+	Issues   []CartValidationIssue
+}
+
+func (e *CartValidationError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.Message)
+	}
+	return fmt.Sprintf("cannot add %d x %s to cart: %s", e.Quantity, e.ItemRef, strings.Join(messages, "; "))
+}
+
+func (e *CartValidationError) Unwrap() error {
+	return ErrCartValidation
+}
+
+func (e *CartValidationError) HasIssue(code string) bool {
+	for _, issue := range e.Issues {
+		if issue.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCartQuantity checks the product state the remote cart enforces:
+// published, stock, minimum and maximum order quantity and allowed quantities.
+// reserved is the quantity already reserved for this cart line; it has been
+// taken off the store's stock and counts as available.
+func (d *ItemDetails) ValidateCartQuantity(quantity, reserved int) []CartValidationIssue {
+	var issues []CartValidationIssue
+
+	if !d.Published {
+		issues = append(issues, CartValidationIssue{
+			Code:    CartIssueNotPublished,
+			Field:   "published",
+			Message: "product is not published",
+		})
+	}
+	if available := d.Availability + reserved; quantity > available {
+		issues = append(issues, CartValidationIssue{
+			Code:    CartIssueInsufficientStock,
+			Field:   "stock_quantity",
+			Message: fmt.Sprintf("requested %d but only %d in stock", quantity, available),
+		})
+	}
+	if d.OrderMinimumQuantity > 0 && quantity < d.OrderMinimumQuantity {
+		issues = append(issues, CartValidationIssue{
+			Code:    CartIssueBelowMinimum,
+			Field:   "order_minimum_quantity",
+			Message: fmt.Sprintf("minimum order quantity is %d", d.OrderMinimumQuantity),
+		})
+	}
+	if d.OrderMaximumQuantity > 0 && quantity > d.OrderMaximumQuantity {
+		issues = append(issues, CartValidationIssue{
+			Code:    CartIssueAboveMaximum,
+			Field:   "order_maximum_quantity",
+			Message: fmt.Sprintf("maximum order quantity is %d", d.OrderMaximumQuantity),
+		})
+	}
+	if len(d.AllowedQuantities) > 0 && !containsInt(d.AllowedQuantities, quantity) {
+		issues = append(issues, CartValidationIssue{
+			Code:    CartIssueQuantityNotAllowed,
+			Field:   "allowed_quantities",
+			Message: fmt.Sprintf("quantity must be one of %v", d.AllowedQuantities),
+		})
+	}
+
+	return issues
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type AuctionWinner struct {
+	Email           string
+	FirstName       string
+	LastName        string
+	Phone           string
+	BillingAddress  Address
+	ShippingAddress *Address
+}
+
+type AuctionLot struct {
+	AuctionID     string
+	LotNumber     string
+	ItemRef       ItemRef
+	Quantity      int
+	BidHistoryURL string
+}
+
+type CheckoutRequest struct {
+	CheckoutID              string
+	Winner                  AuctionWinner
+	Lot                     AuctionLot
+	WinningBid              float64
+	StoreID                 int
+	PaymentMethodSystemName string
+	ShippingMethod          string
+	// ReservationID is the stock reservation held for the lot, if any. The
+	// reserved units are already off the store's stock, so they count as
+	// available when validating the cart. Commit the reservation with
+	// StockService.CommitStock once the checkout completes.
+	ReservationID string
+}
+
+func (r *CheckoutRequest) Validate() error {
+	if r.Winner.Email == "" {
+		return fmt.Errorf("winner email cannot be empty")
+	}
+	if r.Lot.AuctionID == "" {
+		return fmt.Errorf("auction ID cannot be empty")
+	}
+	if err := r.Lot.ItemRef.Validate(); err != nil {
+		return err
+	}
+	if _, ok := r.Lot.ItemRef.NumericID(); !ok {
+		return fmt.Errorf("%w: %s does not reference an ecommerce product", ErrInvalidItemRef, r.Lot.ItemRef)
+	}
+	if r.Lot.Quantity <= 0 {
+		return fmt.Errorf("lot quantity must be positive, got %d", r.Lot.Quantity)
+	}
+	if r.WinningBid <= 0 {
+		return fmt.Errorf("winning bid must be positive, got %.2f", r.WinningBid)
+	}
+	return nil
+}
+
+// ID returns the checkout ID, defaulting to one derived from the auction and lot
+// so that retries for the same win resume the same checkout.
+func (r *CheckoutRequest) ID() string {
+	if r.CheckoutID != "" {
+		return r.CheckoutID
+	}
+	if r.Lot.LotNumber == "" {
+		return r.Lot.AuctionID
+	}
+	return r.Lot.AuctionID + "/" + r.Lot.LotNumber
+}
+
+type CheckoutStep string
+
+const (
+	CheckoutStepValidateCart    CheckoutStep = "validate_cart"
+	CheckoutStepCustomer        CheckoutStep = "customer"
+	CheckoutStepBillingAddress  CheckoutStep = "billing_address"
+	CheckoutStepShippingAddress CheckoutStep = "shipping_address"
+	CheckoutStepClearCart       CheckoutStep = "clear_cart"
+	CheckoutStepCartItem        CheckoutStep = "cart_item"
+	CheckoutStepOrder           CheckoutStep = "order"
+	CheckoutStepItemPrice       CheckoutStep = "item_price"
+	CheckoutStepOrderNote       CheckoutStep = "order_note"
+)
+
+type CheckoutStatus string
+
+const (
+	CheckoutInProgress         CheckoutStatus = "in_progress"
+	CheckoutCompleted          CheckoutStatus = "completed"
+	CheckoutCompensated        CheckoutStatus = "compensated"
+	CheckoutCompensationFailed CheckoutStatus = "compensation_failed"
+)
+
+// CheckoutLog is the persisted state of a checkout saga. It records every
+// completed step together with the remote IDs needed to resume or compensate.
+type CheckoutLog struct {
+	CheckoutID             string
+	Status                 CheckoutStatus
+	CompletedSteps         []CheckoutStep
+	CustomerID             int
+	CustomerCreated        bool
+	BillingAddressID       int
+	BillingAddressCreated  bool
+	ShippingAddressID      int
+	ShippingAddressCreated bool
+	CartItemID             int
+	OrderID                int
+	OrderItemID            int
+	FinalPrice             float64
+	LastError              string
+	UpdatedAt              time.Time
+}
+
+func (l *CheckoutLog) HasCompleted(step CheckoutStep) bool {
+	for _, completed := range l.CompletedSteps {
+		if completed == step {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *CheckoutLog) MarkCompleted(step CheckoutStep) {
+	if !l.HasCompleted(step) {
+		l.CompletedSteps = append(l.CompletedSteps, step)
+	}
+}
+
+type CheckoutResult struct {
+	CheckoutID string
+	CustomerID int
+	Order      *Order
+	FinalPrice float64
+}
@@ -1,6 +1,16 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCustomerNotFound   = errors.New("customer not found")
+	ErrCustomerEmailTaken = errors.New("customer email already in use")
+)
 
 type Customer struct {
 	ID        int       `json:"id"`
@@ -8,6 +18,62 @@ type Customer struct {
 	Name      string    `json:"name"`
 	Phone     string    `json:"phone"`
 	Address   string    `json:"address"`
+	RoleIDs   []int     `json:"role_ids,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
+
+type CustomerInput struct {
+	Email     string `json:"email"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	Password  string `json:"password,omitempty"`
+	RoleIDs   []int  `json:"role_ids,omitempty"`
+}
+
+// NormalizeEmail trims and lower-cases an email so that addresses differing
+// only in case or surrounding whitespace compare equal.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (c *Customer) HasEmail(email string) bool {
+	return NormalizeEmail(c.Email) == NormalizeEmail(email)
+}
+
+// CustomerUpdate is a partial customer update: only non-nil fields are sent,
+// and a pointer to an empty string clears the field.
+type CustomerUpdate struct {
+	Email     *string
+	FirstName *string
+	LastName  *string
+	Phone     *string
+}
+
+func (u CustomerUpdate) Validate() error {
+	if u.Email == nil && u.FirstName == nil && u.LastName == nil && u.Phone == nil {
+		return fmt.Errorf("customer update has no fields")
+	}
+	if u.Email != nil && NormalizeEmail(*u.Email) == "" {
+		return fmt.Errorf("customer email cannot be empty")
+	}
+	return nil
+}
+
+func (u CustomerUpdate) Fields() map[string]interface{} {
+	fields := make(map[string]interface{})
+	if u.Email != nil {
+		fields["email"] = NormalizeEmail(*u.Email)
+	}
+	if u.FirstName != nil {
+		fields["first_name"] = strings.TrimSpace(*u.FirstName)
+	}
+	if u.LastName != nil {
+		fields["last_name"] = strings.TrimSpace(*u.LastName)
+	}
+	if u.Phone != nil {
+		fields["phone"] = strings.TrimSpace(*u.Phone)
+	}
+	return fields
+}
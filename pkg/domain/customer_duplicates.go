@@ -0,0 +1,297 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type DuplicateReason string
+
+const (
+	DuplicateReasonEmail   DuplicateReason = "email"
+	DuplicateReasonPhone   DuplicateReason = "phone"
+	DuplicateReasonName    DuplicateReason = "name"
+	DuplicateReasonAddress DuplicateReason = "address"
+)
+
+const (
+	duplicateEmailWeight   = 1.0
+	duplicatePhoneWeight   = 0.6
+	duplicateNameWeight    = 0.3
+	duplicateAddressWeight = 0.2
+
+	// fuzzyMatchThreshold is the minimum similarity for a name or address to
+	// count as matching.
+	fuzzyMatchThreshold = 0.85
+)
+
+// DuplicateCandidate proposes merging DuplicateCustomerID into
+// PrimaryCustomerID. Score is between 0 and 1; an exact email match alone
+// scores 1.
+type DuplicateCandidate struct {
+	PrimaryCustomerID   int
+	DuplicateCustomerID int
+	Score               float64
+	Reasons             []DuplicateReason
+	PrimaryOrderCount   int
+	DuplicateOrderCount int
+}
+
+type OrderReassignment struct {
+	OrderID        int
+	FromCustomerID int
+	ToCustomerID   int
+	Error          string
+}
+
+type DuplicateReport struct {
+	CustomersScanned int
+	Candidates       []DuplicateCandidate
+	Reassignments    []OrderReassignment
+	DryRun           bool
+}
+
+// ScoreDuplicate compares two customers by normalized email, phone, and fuzzy
+// name and address similarity.
+func ScoreDuplicate(a, b *Customer) (float64, []DuplicateReason) {
+	var score float64
+	var reasons []DuplicateReason
+
+	if email := NormalizeEmail(a.Email); email != "" && email == NormalizeEmail(b.Email) {
+		score += duplicateEmailWeight
+		reasons = append(reasons, DuplicateReasonEmail)
+	}
+	if phone := phoneMatchKey(a.Phone); phone != "" && phone == phoneMatchKey(b.Phone) {
+		score += duplicatePhoneWeight
+		reasons = append(reasons, DuplicateReasonPhone)
+	}
+	if similarity := textSimilarity(nameMatchKey(a.Name), nameMatchKey(b.Name)); similarity >= fuzzyMatchThreshold {
+		score += duplicateNameWeight * similarity
+		reasons = append(reasons, DuplicateReasonName)
+	}
+	if similarity := textSimilarity(normalizeText(a.Address), normalizeText(b.Address)); similarity >= fuzzyMatchThreshold {
+		score += duplicateAddressWeight * similarity
+		reasons = append(reasons, DuplicateReasonAddress)
+	}
+
+	if score > 1 {
+		score = 1
+	}
+	return RoundPrice(score), reasons
+}
+
+// FindDuplicateCustomers groups customers whose pairwise score reaches
+// minScore. In every group the customer with the most orders, or the lowest ID
+// on a tie, is the primary; each other member becomes a candidate scored
+// directly against the primary. A member that joined the group through a
+// chain of links may therefore score below minScore. Candidates are ranked by
+// score.
+func FindDuplicateCustomers(customers []Customer, orderCounts map[int]int, minScore float64) []DuplicateCandidate {
+	parent := make([]int, len(customers))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	linked := make(map[int]bool)
+	for _, pair := range duplicatePairs(customers) {
+		i, j := pair[0], pair[1]
+		score, _ := ScoreDuplicate(&customers[i], &customers[j])
+		if score < minScore || score == 0 {
+			continue
+		}
+
+		parent[find(i)] = find(j)
+		linked[i], linked[j] = true, true
+	}
+
+	groups := make(map[int][]int)
+	for i := range customers {
+		if linked[i] {
+			root := find(i)
+			groups[root] = append(groups[root], i)
+		}
+	}
+
+	var candidates []DuplicateCandidate
+	for _, members := range groups {
+		primary := members[0]
+		for _, m := range members[1:] {
+			if isBetterPrimary(&customers[m], &customers[primary], orderCounts) {
+				primary = m
+			}
+		}
+
+		for _, m := range members {
+			if m == primary {
+				continue
+			}
+			score, reasons := ScoreDuplicate(&customers[primary], &customers[m])
+			candidates = append(candidates, DuplicateCandidate{
+				PrimaryCustomerID:   customers[primary].ID,
+				DuplicateCustomerID: customers[m].ID,
+				Score:               score,
+				Reasons:             reasons,
+				PrimaryOrderCount:   orderCounts[customers[primary].ID],
+				DuplicateOrderCount: orderCounts[customers[m].ID],
+			})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].DuplicateCustomerID < candidates[j].DuplicateCustomerID
+	})
+	return candidates
+}
+
+// duplicatePairs returns the index pairs worth scoring: customers sharing an
+// email, a phone or a coarse name key. This avoids comparing every pair.
+func duplicatePairs(customers []Customer) [][2]int {
+	blocks := make(map[string][]int)
+	for i := range customers {
+		c := &customers[i]
+		if email := NormalizeEmail(c.Email); email != "" {
+			blocks["e:"+email] = append(blocks["e:"+email], i)
+		}
+		if phone := phoneMatchKey(c.Phone); phone != "" {
+			blocks["p:"+phone] = append(blocks["p:"+phone], i)
+		}
+		if key := nameBlockKey(c.Name); key != "" {
+			blocks["n:"+key] = append(blocks["n:"+key], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	var pairs [][2]int
+	for _, members := range blocks {
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				pair := [2]int{members[x], members[y]}
+				if !seen[pair] {
+					seen[pair] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+func isBetterPrimary(a, b *Customer, orderCounts map[int]int) bool {
+	if orderCounts[a.ID] != orderCounts[b.ID] {
+		return orderCounts[a.ID] > orderCounts[b.ID]
+	}
+	return a.ID < b.ID
+}
+
+// phoneMatchKey compares phones by their last nine digits. This is a
+// heuristic rather than NormalizePhoneE164: customers carry no country to
+// resolve national numbers against, and the suffix ignores country prefixes,
+// trunk zeros and formatting at the cost of rare false matches.
+func phoneMatchKey(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	key := digits.String()
+	if len(key) < 7 {
+		return ""
+	}
+	if len(key) > 9 {
+		key = key[len(key)-9:]
+	}
+	return key
+}
+
+// nameMatchKey normalizes a name and sorts its words, so "Pérez, Ana" and
+// "ana perez" compare equal.
+func nameMatchKey(name string) string {
+	words := strings.Fields(normalizeText(name))
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+func nameBlockKey(name string) string {
+	words := strings.Fields(nameMatchKey(name))
+	if len(words) == 0 {
+		return ""
+	}
+	var key strings.Builder
+	for _, word := range words {
+		key.WriteByte(word[0])
+	}
+	return key.String()
+}
+
+// normalizeText lower-cases, strips common Spanish accents and replaces
+// punctuation with spaces.
+func normalizeText(value string) string {
+	replacer := strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+	value = replacer.Replace(strings.ToLower(value))
+
+	var b strings.Builder
+	for _, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// textSimilarity returns 1 minus the normalized Levenshtein distance.
+func textSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CustomerDataExport bundles everything the store holds about a customer, for
+// data-subject access requests.
+type CustomerDataExport struct {
+	ExportedAt    time.Time          `json:"exported_at"`
+	Customer      *Customer          `json:"customer"`
+	Addresses     []Address          `json:"addresses"`
+	CartItems     []ShoppingCartItem `json:"cart_items"`
+	WishlistItems []ShoppingCartItem `json:"wishlist_items"`
+	Orders        []Order            `json:"orders"`
+}
+
+func (e *CustomerDataExport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(e); err != nil {
+		return fmt.Errorf("failed to encode customer export: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the bundle as one row per field, with the columns section,
+// record_id, field and value. Nested values such as order line items are
+// flattened into dotted field names, e.g. order_items.0.quantity. Fields are
+// written in the order they are declared on the domain types, so the layout
+// is the same for every export.
+func (e *CustomerDataExport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"section", "record_id", "field", "value"}); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		name    string
+		records []interface{}
+	}{
+		{"customer", []interface{}{e.Customer}},
+		{"address", toRecords(len(e.Addresses), func(i int) interface{} { return e.Addresses[i] })},
+		{"cart_item", toRecords(len(e.CartItems), func(i int) interface{} { return e.CartItems[i] })},
+		{"wishlist_item", toRecords(len(e.WishlistItems), func(i int) interface{} { return e.WishlistItems[i] })},
+		{"order", toRecords(len(e.Orders), func(i int) interface{} { return e.Orders[i] })},
+	}
+
+	for _, section := range sections {
+		for _, record := range section.records {
+			fields, err := flattenRecord(record)
+			if err != nil {
+				return fmt.Errorf("failed to flatten %s record: %w", section.name, err)
+			}
+
+			recordID := ""
+			for _, field := range fields {
+				if field.name == "id" {
+					recordID = field.value
+					break
+				}
+			}
+			for _, field := range fields {
+				if err := writer.Write([]string{section.name, recordID, field.name, field.value}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func toRecords(n int, at func(i int) interface{}) []interface{} {
+	records := make([]interface{}, n)
+	for i := range records {
+		records[i] = at(i)
+	}
+	return records
+}
+
+type recordField struct {
+	name  string
+	value string
+}
+
+// flattenRecord walks the JSON encoding of record token by token, which keeps
+// struct fields in declaration order and list elements in index order.
+func flattenRecord(record interface{}) ([]recordField, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var fields []recordField
+	if err := flattenValue(decoder, "", &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func flattenValue(decoder *json.Decoder, prefix string, fields *[]recordField) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		for i := 0; decoder.More(); i++ {
+			key := strconv.Itoa(i)
+			if v == '{' {
+				name, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				key = name.(string)
+			}
+			if err := flattenValue(decoder, joinFieldPath(prefix, key), fields); err != nil {
+				return err
+			}
+		}
+		_, err := decoder.Token()
+		return err
+	case nil:
+		if prefix != "" {
+			*fields = append(*fields, recordField{name: prefix})
+		}
+	default:
+		*fields = append(*fields, recordField{name: prefix, value: fmt.Sprint(v)})
+	}
+	return nil
+}
+
+func joinFieldPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	CustomerRoleRegistered = "Registered"
+	CustomerRoleGuests     = "Guests"
+
+	// DefaultRegisteredRoleID is the ID of the Registered role in a default
+	// installation; it is used when the store's roles cannot be listed.
+	DefaultRegisteredRoleID = 3
+)
+
+type CustomerRole struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	SystemName   string `json:"system_name"`
+	Active       bool   `json:"active"`
+	IsSystemRole bool   `json:"is_system_role"`
+}
+
+func FindCustomerRole(roles []CustomerRole, systemName string) (*CustomerRole, bool) {
+	for i := range roles {
+		if strings.EqualFold(roles[i].SystemName, systemName) {
+			return &roles[i], true
+		}
+	}
+	return nil, false
+}
+
+type CustomerRegistration string
+
+const (
+	CustomerRegistrationAny        CustomerRegistration = ""
+	CustomerRegistrationGuest      CustomerRegistration = "guest"
+	CustomerRegistrationRegistered CustomerRegistration = "registered"
+)
+
+// CustomerQuery filters customers. Zero values mean "no filter"; a zero Page
+// fetches every page of results. A role can be given by ID or by system name,
+// and Registration selects the Guests or Registered role.
+type CustomerQuery struct {
+	RoleID         int
+	RoleSystemName string
+	Registration   CustomerRegistration
+	Email          string
+	CreatedAtMin   *time.Time
+	CreatedAtMax   *time.Time
+	Page           int
+	Limit          int
+}
+
+// RoleName returns the system name of the role the query filters on, if any.
+func (q CustomerQuery) RoleName() string {
+	if q.RoleSystemName != "" {
+		return q.RoleSystemName
+	}
+	switch q.Registration {
+	case CustomerRegistrationGuest:
+		return CustomerRoleGuests
+	case CustomerRegistrationRegistered:
+		return CustomerRoleRegistered
+	}
+	return ""
+}
+
+// Matches reports whether the customer satisfies the filters of the query that
+// can be checked locally. Role and creation date are only checked when the
+// customer carries them.
+func (q CustomerQuery) Matches(customer *Customer, roleID int) bool {
+	if q.Email != "" && !strings.Contains(NormalizeEmail(customer.Email), NormalizeEmail(q.Email)) {
+		return false
+	}
+	if roleID != 0 && len(customer.RoleIDs) > 0 && !containsInt(customer.RoleIDs, roleID) {
+		return false
+	}
+	if !customer.CreatedAt.IsZero() {
+		if q.CreatedAtMin != nil && customer.CreatedAt.Before(*q.CreatedAtMin) {
+			return false
+		}
+		if q.CreatedAtMax != nil && customer.CreatedAt.After(*q.CreatedAtMax) {
+			return false
+		}
+	}
+	return true
+}
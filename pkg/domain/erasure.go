@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const ErasedValue = "[erased]"
+
+type ErasureMode string
+
+const (
+	ErasureAnonymize ErasureMode = "anonymize"
+	ErasureDelete    ErasureMode = "delete"
+)
+
+func (m ErasureMode) Validate() error {
+	switch m {
+	case ErasureAnonymize, ErasureDelete:
+		return nil
+	default:
+		return fmt.Errorf("invalid erasure mode %q", m)
+	}
+}
+
+// OrderPersonalData lists the fields of an order that still identify the
+// erased customer. Orders are kept for accounting, so they must be reviewed
+// separately.
+type OrderPersonalData struct {
+	OrderID int
+	Fields  []string
+}
+
+type ErasureReport struct {
+	CustomerID             int
+	Mode                   ErasureMode
+	CustomerErased         bool
+	ErasedAddressIDs       []int
+	OrdersWithPersonalData []OrderPersonalData
+}
+
+// AnonymizedEmail returns a unique, undeliverable email for an erased customer.
+func AnonymizedEmail(customerID int) string {
+	return fmt.Sprintf("erased-%d@anonymized.invalid", customerID)
+}
+
+func AnonymizedCustomerUpdate(customerID int) CustomerUpdate {
+	email := AnonymizedEmail(customerID)
+	erased := ErasedValue
+	empty := ""
+	return CustomerUpdate{Email: &email, FirstName: &erased, LastName: &erased, Phone: &empty}
+}
+
+// AnonymizedFields returns the address update that keeps the region and
+// replaces everything that identifies a person or a street location. Fields
+// that are blanked are sent explicitly as empty strings.
+func (a Address) AnonymizedFields(email string) map[string]interface{} {
+	return map[string]interface{}{
+		"first_name":        ErasedValue,
+		"last_name":         ErasedValue,
+		"email":             email,
+		"company":           "",
+		"country_id":        a.CountryID,
+		"state_province_id": a.StateProvinceID,
+		"city":              ErasedValue,
+		"address1":          ErasedValue,
+		"address2":          "",
+		"zip_postal_code":   ErasedValue,
+		"phone_number":      "",
+	}
+}
+
+func (a *Address) HasPersonalData() bool {
+	if a == nil {
+		return false
+	}
+	for _, value := range []string{a.FirstName, a.LastName, a.Email, a.Company, a.Address1, a.Address2, a.ZipPostalCode, a.PhoneNumber} {
+		value = strings.TrimSpace(value)
+		if value != "" && value != ErasedValue && !strings.HasSuffix(value, "@anonymized.invalid") {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Order) PersonalDataFields() []string {
+	var fields []string
+	if o.BillingAddress.HasPersonalData() {
+		fields = append(fields, "billing_address")
+	}
+	if o.ShippingAddress.HasPersonalData() {
+		fields = append(fields, "shipping_address")
+	}
+	return fields
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrCountryNotFound = errors.New("country not found")
+	ErrStateNotFound   = errors.New("state or province not found")
+)
+
+type Country struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	TwoLetterIsoCode   string `json:"two_letter_iso_code"`
+	ThreeLetterIsoCode string `json:"three_letter_iso_code"`
+	Published          bool   `json:"published"`
+}
+
+type StateProvince struct {
+	ID           int    `json:"id"`
+	CountryID    int    `json:"country_id"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	Published    bool   `json:"published"`
+}
+
+// FindCountry looks a country up by two or three letter ISO code, or by name
+// ignoring case and accents. Common Spanish and English names are also
+// recognized, so "México" finds the country named "Mexico". The result is a
+// copy, not a pointer into countries.
+func FindCountry(countries []Country, query string) (*Country, bool) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, false
+	}
+
+	iso, _ := CountryISOCode(query)
+	name := normalizeText(query)
+	for _, c := range countries {
+		if strings.EqualFold(c.TwoLetterIsoCode, query) || strings.EqualFold(c.ThreeLetterIsoCode, query) ||
+			(iso != "" && strings.EqualFold(c.TwoLetterIsoCode, iso)) || normalizeText(c.Name) == name {
+			return &c, true
+		}
+	}
+	return nil, false
+}
+
+// FindState looks a state or province up by abbreviation or by name, ignoring
+// case and accents. The result is a copy, not a pointer into states.
+func FindState(states []StateProvince, query string) (*StateProvince, bool) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, false
+	}
+
+	name := normalizeText(query)
+	for _, state := range states {
+		if strings.EqualFold(state.Abbreviation, query) || normalizeText(state.Name) == name {
+			return &state, true
+		}
+	}
+	return nil, false
+}
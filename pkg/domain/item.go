@@ -33,3 +33,7 @@ func (o *Item) Update(name, description, externalId, pointOfSaleId, url string)
 
 	return nil
 }
+
+func (o *Item) Ref() (ItemRef, error) {
+	return ParseItemRef(o.ItemId)
+}
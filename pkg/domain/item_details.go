@@ -1,7 +1,29 @@
 package domain
 
+import (
+	"strconv"
+	"strings"
+)
+
 type ItemDetails struct {
 	Item
-	Availability int
-	Price        float64
+	Availability         int
+	Price                float64
+	Published            bool
+	OrderMinimumQuantity int
+	OrderMaximumQuantity int
+	AllowedQuantities    []int
+}
+
+// ParseAllowedQuantities parses the comma separated allowed quantities of a
+// product, e.g. "1, 5, 10". Invalid entries are ignored.
+func ParseAllowedQuantities(value string) []int {
+	var quantities []int
+	for _, part := range strings.Split(value, ",") {
+		quantity, err := strconv.Atoi(strings.TrimSpace(part))
+		if err == nil && quantity > 0 {
+			quantities = append(quantities, quantity)
+		}
+	}
+	return quantities
 }
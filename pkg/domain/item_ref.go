@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	KivioEcommerceProvider = "kivio-ecommerce"
+	ItemRefSeparator       = "∼"
+)
+
+var ErrInvalidItemRef = errors.New("invalid item ref")
+
+// ItemRef identifies an item in an external provider as "<provider>∼<remoteId>".
+type ItemRef struct {
+	Provider string
+	RemoteID string
+}
+
+func NewItemRef(provider, remoteID string) (ItemRef, error) {
+	ref := ItemRef{Provider: provider, RemoteID: remoteID}
+	if err := ref.Validate(); err != nil {
+		return ItemRef{}, err
+	}
+	return ref, nil
+}
+
+func NewEcommerceItemRef(productID int) ItemRef {
+	return ItemRef{Provider: KivioEcommerceProvider, RemoteID: strconv.Itoa(productID)}
+}
+
+func ParseItemRef(value string) (ItemRef, error) {
+	provider, remoteID, found := strings.Cut(value, ItemRefSeparator)
+	if !found {
+		return ItemRef{}, fmt.Errorf("%w: %q is missing the %q separator", ErrInvalidItemRef, value, ItemRefSeparator)
+	}
+	return NewItemRef(provider, remoteID)
+}
+
+func (r ItemRef) Validate() error {
+	if r.Provider == "" {
+		return fmt.Errorf("%w: provider cannot be empty", ErrInvalidItemRef)
+	}
+	if r.RemoteID == "" {
+		return fmt.Errorf("%w: remote ID cannot be empty", ErrInvalidItemRef)
+	}
+	if strings.Contains(r.Provider, ItemRefSeparator) || strings.Contains(r.RemoteID, ItemRefSeparator) {
+		return fmt.Errorf("%w: %q contains more than one separator", ErrInvalidItemRef, r.Provider+ItemRefSeparator+r.RemoteID)
+	}
+	if strings.IndexFunc(r.Provider+r.RemoteID, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%w: %q contains whitespace", ErrInvalidItemRef, r.Provider+ItemRefSeparator+r.RemoteID)
+	}
+	return nil
+}
+
+func (r ItemRef) String() string {
+	if r.IsZero() {
+		return ""
+	}
+	return r.Provider + ItemRefSeparator + r.RemoteID
+}
+
+func (r ItemRef) IsZero() bool {
+	return r.Provider == "" && r.RemoteID == ""
+}
+
+// NumericID returns the remote ID as an integer when the provider uses numeric IDs.
+func (r ItemRef) NumericID() (int, bool) {
+	id, err := strconv.Atoi(r.RemoteID)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderIdempotencyKeyAttribute is the order custom value used to tag orders
+// created with an idempotency key, such as the auction ID.
+const OrderIdempotencyKeyAttribute = "IdempotencyKey"
+
+type OrderStatus string
+
+const (
+	OrderStatusPending    OrderStatus = "Pending"
+	OrderStatusProcessing OrderStatus = "Processing"
+	OrderStatusComplete   OrderStatus = "Complete"
+	OrderStatusCancelled  OrderStatus = "Cancelled"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending           PaymentStatus = "Pending"
+	PaymentStatusAuthorized        PaymentStatus = "Authorized"
+	PaymentStatusPaid              PaymentStatus = "Paid"
+	PaymentStatusPartiallyRefunded PaymentStatus = "PartiallyRefunded"
+	PaymentStatusRefunded          PaymentStatus = "Refunded"
+	PaymentStatusVoided            PaymentStatus = "Voided"
+)
+
+type ShippingStatus string
+
+const (
+	ShippingStatusNotRequired      ShippingStatus = "ShippingNotRequired"
+	ShippingStatusNotYetShipped    ShippingStatus = "NotYetShipped"
+	ShippingStatusPartiallyShipped ShippingStatus = "PartiallyShipped"
+	ShippingStatusShipped          ShippingStatus = "Shipped"
+	ShippingStatusDelivered        ShippingStatus = "Delivered"
+)
+
+type Order struct {
+	ID                      int                    `json:"id,omitempty"`
+	CustomerID              int                    `json:"customer_id,omitempty"`
+	StoreID                 int                    `json:"store_id,omitempty"`
+	OrderStatus             OrderStatus            `json:"order_status,omitempty"`
+	PaymentStatus           PaymentStatus          `json:"payment_status,omitempty"`
+	ShippingStatus          ShippingStatus         `json:"shipping_status,omitempty"`
+	PaymentMethodSystemName string                 `json:"payment_method_system_name,omitempty"`
+	ShippingMethod          string                 `json:"shipping_method,omitempty"`
+	OrderSubtotalInclTax    float64                `json:"order_subtotal_incl_tax,omitempty"`
+	OrderSubtotalExclTax    float64                `json:"order_subtotal_excl_tax,omitempty"`
+	OrderShippingInclTax    float64                `json:"order_shipping_incl_tax,omitempty"`
+	OrderShippingExclTax    float64                `json:"order_shipping_excl_tax,omitempty"`
+	OrderTax                float64                `json:"order_tax,omitempty"`
+	OrderDiscount           float64                `json:"order_discount,omitempty"`
+	OrderTotal              float64                `json:"order_total,omitempty"`
+	RefundedAmount          float64                `json:"refunded_amount,omitempty"`
+	CreatedOnUtc            *time.Time             `json:"created_on_utc,omitempty"`
+	BillingAddress          *Address               `json:"billing_address,omitempty"`
+	ShippingAddress         *Address               `json:"shipping_address,omitempty"`
+	OrderItems              []OrderItem            `json:"order_items,omitempty"`
+	CustomValues            map[string]interface{} `json:"custom_values,omitempty"`
+}
+
+type OrderItem struct {
+	ID               int     `json:"id,omitempty"`
+	ProductID        int     `json:"product_id,omitempty"`
+	Quantity         int     `json:"quantity,omitempty"`
+	UnitPriceInclTax float64 `json:"unit_price_incl_tax"`
+	UnitPriceExclTax float64 `json:"unit_price_excl_tax"`
+	PriceInclTax     float64 `json:"price_incl_tax"`
+	PriceExclTax     float64 `json:"price_excl_tax"`
+}
+
+func (o *Order) FindItemByProductID(productID int) (*OrderItem, bool) {
+	for i := range o.OrderItems {
+		if o.OrderItems[i].ProductID == productID {
+			return &o.OrderItems[i], true
+		}
+	}
+	return nil, false
+}
+
+func (o *Order) CustomValue(key string) string {
+	value, ok := o.CustomValues[key]
+	if !ok || value == nil {
+		return ""
+	}
+	if str, ok := value.(string); ok {
+		return str
+	}
+	return fmt.Sprint(value)
+}
+
+func (o *Order) SetCustomValue(key, value string) {
+	if o.CustomValues == nil {
+		o.CustomValues = make(map[string]interface{})
+	}
+	o.CustomValues[key] = value
+}
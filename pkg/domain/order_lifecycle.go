@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	OrderTrackingNumberAttribute     = "TrackingNumber"
+	OrderCancellationReasonAttribute = "CancellationReason"
+)
+
+var ErrIllegalOrderTransition = errors.New("illegal order transition")
+
+type OrderTransition string
+
+const (
+	OrderTransitionMarkPaid    OrderTransition = "mark_paid"
+	OrderTransitionMarkShipped OrderTransition = "mark_shipped"
+	OrderTransitionCancel      OrderTransition = "cancel"
+	OrderTransitionComplete    OrderTransition = "complete"
+)
+
+type OrderTransitionError struct {
+	OrderID        int
+	Transition     OrderTransition
+	OrderStatus    OrderStatus
+	PaymentStatus  PaymentStatus
+	ShippingStatus ShippingStatus
+	Reason         string
+}
+
+func (e *OrderTransitionError) Error() string {
+	return fmt.Sprintf("cannot %s order %d (order: %s, payment: %s, shipping: %s): %s",
+		e.Transition, e.OrderID, e.OrderStatus, e.PaymentStatus, e.ShippingStatus, e.Reason)
+}
+
+func (e *OrderTransitionError) Unwrap() error {
+	return ErrIllegalOrderTransition
+}
+
+func (o *Order) IsOpen() bool {
+	return o.OrderStatus == OrderStatusPending || o.OrderStatus == OrderStatusProcessing
+}
+
+// MarkPaid records the payment of a pending or authorized open order and moves
+// a pending order to processing.
+func (o *Order) MarkPaid() error {
+	if !o.IsOpen() {
+		return o.transitionError(OrderTransitionMarkPaid, "order is not open")
+	}
+	if o.PaymentStatus != PaymentStatusPending && o.PaymentStatus != PaymentStatusAuthorized {
+		return o.transitionError(OrderTransitionMarkPaid, "payment is not pending or authorized")
+	}
+
+	o.PaymentStatus = PaymentStatusPaid
+	o.OrderStatus = OrderStatusProcessing
+	return nil
+}
+
+func (o *Order) MarkShipped(trackingNumber string) error {
+	if !o.IsOpen() {
+		return o.transitionError(OrderTransitionMarkShipped, "order is not open")
+	}
+	if o.ShippingStatus != ShippingStatusNotYetShipped && o.ShippingStatus != ShippingStatusPartiallyShipped {
+		return o.transitionError(OrderTransitionMarkShipped, "order is not awaiting shipment")
+	}
+
+	o.ShippingStatus = ShippingStatusShipped
+	o.OrderStatus = OrderStatusProcessing
+	if trackingNumber != "" {
+		o.SetCustomValue(OrderTrackingNumberAttribute, trackingNumber)
+	}
+	return nil
+}
+
+func (o *Order) Cancel(reason string) error {
+	if !o.IsOpen() {
+		return o.transitionError(OrderTransitionCancel, "order is not open")
+	}
+	if o.ShippingStatus == ShippingStatusShipped || o.ShippingStatus == ShippingStatusDelivered {
+		return o.transitionError(OrderTransitionCancel, "order has already been shipped")
+	}
+
+	o.OrderStatus = OrderStatusCancelled
+	if reason != "" {
+		o.SetCustomValue(OrderCancellationReasonAttribute, reason)
+	}
+	return nil
+}
+
+func (o *Order) Complete() error {
+	if o.OrderStatus != OrderStatusProcessing {
+		return o.transitionError(OrderTransitionComplete, "order is not processing")
+	}
+	if o.PaymentStatus != PaymentStatusPaid {
+		return o.transitionError(OrderTransitionComplete, "order is not paid")
+	}
+	switch o.ShippingStatus {
+	case ShippingStatusShipped, ShippingStatusDelivered, ShippingStatusNotRequired:
+	default:
+		return o.transitionError(OrderTransitionComplete, "order has not been shipped")
+	}
+
+	o.OrderStatus = OrderStatusComplete
+	return nil
+}
+
+func (o *Order) transitionError(transition OrderTransition, reason string) error {
+	return &OrderTransitionError{
+		OrderID:        o.ID,
+		Transition:     transition,
+		OrderStatus:    o.OrderStatus,
+		PaymentStatus:  o.PaymentStatus,
+		ShippingStatus: o.ShippingStatus,
+		Reason:         reason,
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type OrderNote struct {
+	ID                int        `json:"id,omitempty"`
+	OrderID           int        `json:"order_id"`
+	Note              string     `json:"note"`
+	DisplayToCustomer bool       `json:"display_to_customer"`
+	CreatedOnUtc      *time.Time `json:"created_on_utc,omitempty"`
+}
+
+// AuctionNote describes the auction that produced an order, so the order can
+// be traced back to it from the merchant admin.
+type AuctionNote struct {
+	AuctionID     string
+	LotNumber     string
+	WinningBid    float64
+	BidHistoryURL string
+}
+
+func (n AuctionNote) String() string {
+	parts := []string{fmt.Sprintf("Auction: %s", n.AuctionID)}
+	if n.LotNumber != "" {
+		parts = append(parts, fmt.Sprintf("Lot: %s", n.LotNumber))
+	}
+	if n.WinningBid > 0 {
+		parts = append(parts, fmt.Sprintf("Winning bid: %.2f", n.WinningBid))
+	}
+	if n.BidHistoryURL != "" {
+		parts = append(parts, fmt.Sprintf("Bid history: %s", n.BidHistoryURL))
+	}
+	return strings.Join(parts, " | ")
+}
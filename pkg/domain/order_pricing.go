@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrOrderTotalsMismatch = errors.New("order totals do not match after update")
+
+type OrderTotalsMode int
+
+const (
+	// OrderTotalsExplicit recomputes subtotal, tax and total locally and sends
+	// them with the order update.
+	OrderTotalsExplicit OrderTotalsMode = iota
+	// OrderTotalsServer only updates the line item and relies on the store to
+	// recompute the order totals.
+	OrderTotalsServer
+)
+
+// OrderItemPriceOptions configures SetOrderItemPrice and SetOrderItemLineTotal.
+// Prices are tax exclusive and TaxRate is a fraction, e.g. 0.16 for 16%.
+type OrderItemPriceOptions struct {
+	Mode    OrderTotalsMode
+	TaxRate float64
+}
+
+const priceTolerance = 0.005
+
+func RoundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func pricesEqual(a, b float64) bool {
+	return math.Abs(a-b) < priceTolerance
+}
+
+// SetItemUnitPrice updates the unit and line prices of an order item.
+func (o *Order) SetItemUnitPrice(orderItemID int, unitPrice, taxRate float64) (*OrderItem, error) {
+	if unitPrice < 0 {
+		return nil, fmt.Errorf("unit price cannot be negative, got %.2f", unitPrice)
+	}
+	if taxRate < 0 {
+		return nil, fmt.Errorf("tax rate cannot be negative, got %.4f", taxRate)
+	}
+
+	item, ok := o.findItem(orderItemID)
+	if !ok {
+		return nil, fmt.Errorf("order %d has no item %d", o.ID, orderItemID)
+	}
+
+	quantity := float64(item.Quantity)
+	item.UnitPriceExclTax = RoundPrice(unitPrice)
+	item.UnitPriceInclTax = RoundPrice(unitPrice * (1 + taxRate))
+	item.PriceExclTax = RoundPrice(item.UnitPriceExclTax * quantity)
+	item.PriceInclTax = RoundPrice(item.UnitPriceInclTax * quantity)
+	return item, nil
+}
+
+// SetItemLineTotal updates the line prices of an order item to lineTotal
+// exactly. The unit prices are rounded from it, so unit price times quantity
+// may differ from the line total by the rounding remainder.
+func (o *Order) SetItemLineTotal(orderItemID int, lineTotal, taxRate float64) (*OrderItem, error) {
+	if lineTotal < 0 {
+		return nil, fmt.Errorf("line total cannot be negative, got %.2f", lineTotal)
+	}
+
+	current, ok := o.findItem(orderItemID)
+	if !ok {
+		return nil, fmt.Errorf("order %d has no item %d", o.ID, orderItemID)
+	}
+	if current.Quantity <= 0 {
+		return nil, fmt.Errorf("order item %d has no quantity", orderItemID)
+	}
+
+	item, err := o.SetItemUnitPrice(orderItemID, lineTotal/float64(current.Quantity), taxRate)
+	if err != nil {
+		return nil, err
+	}
+
+	item.PriceExclTax = RoundPrice(lineTotal)
+	item.PriceInclTax = RoundPrice(lineTotal * (1 + taxRate))
+	return item, nil
+}
+
+// RecalculateTotals derives subtotal, tax and total from the line items,
+// shipping and discount of the order.
+func (o *Order) RecalculateTotals() {
+	var subtotalExcl, subtotalIncl float64
+	for _, item := range o.OrderItems {
+		subtotalExcl += item.PriceExclTax
+		subtotalIncl += item.PriceInclTax
+	}
+
+	o.OrderSubtotalExclTax = RoundPrice(subtotalExcl)
+	o.OrderSubtotalInclTax = RoundPrice(subtotalIncl)
+	o.OrderTax = RoundPrice((subtotalIncl - subtotalExcl) + (o.OrderShippingInclTax - o.OrderShippingExclTax))
+	o.OrderTotal = RoundPrice(subtotalIncl + o.OrderShippingInclTax - o.OrderDiscount)
+}
+
+// VerifyItemPrice compares an order read back from the store with the expected
+// item prices and, when checkTotals is set, the expected totals.
+func (o *Order) VerifyItemPrice(expected *Order, orderItemID int, checkTotals bool) error {
+	want, ok := expected.findItem(orderItemID)
+	if !ok {
+		return fmt.Errorf("order %d has no item %d", expected.ID, orderItemID)
+	}
+	got, ok := o.findItem(orderItemID)
+	if !ok {
+		return fmt.Errorf("%w: order %d no longer has item %d", ErrOrderTotalsMismatch, o.ID, orderItemID)
+	}
+
+	if !pricesEqual(got.UnitPriceExclTax, want.UnitPriceExclTax) || !pricesEqual(got.PriceInclTax, want.PriceInclTax) {
+		return fmt.Errorf("%w: item %d unit price %.2f (expected %.2f), line total %.2f (expected %.2f)",
+			ErrOrderTotalsMismatch, orderItemID, got.UnitPriceExclTax, want.UnitPriceExclTax, got.PriceInclTax, want.PriceInclTax)
+	}
+
+	if !checkTotals {
+		return nil
+	}
+	if !pricesEqual(o.OrderSubtotalInclTax, expected.OrderSubtotalInclTax) ||
+		!pricesEqual(o.OrderTax, expected.OrderTax) ||
+		!pricesEqual(o.OrderTotal, expected.OrderTotal) {
+		return fmt.Errorf("%w: subtotal %.2f (expected %.2f), tax %.2f (expected %.2f), total %.2f (expected %.2f)",
+			ErrOrderTotalsMismatch, o.OrderSubtotalInclTax, expected.OrderSubtotalInclTax, o.OrderTax, expected.OrderTax, o.OrderTotal, expected.OrderTotal)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// OrderQuery filters orders. Zero values mean "no filter"; a zero Page fetches
+// every page of results.
+type OrderQuery struct {
+	CustomerID     int
+	OrderStatus    OrderStatus
+	PaymentStatus  PaymentStatus
+	ShippingStatus ShippingStatus
+	CreatedAtMin   *time.Time
+	CreatedAtMax   *time.Time
+	StoreID        int
+	Page           int
+	Limit          int
+}
+
+// Matches reports whether the order satisfies every filter of the query. It is
+// used to enforce filters the remote API may ignore.
+func (q OrderQuery) Matches(order *Order) bool {
+	if q.CustomerID != 0 && order.CustomerID != q.CustomerID {
+		return false
+	}
+	if q.OrderStatus != "" && order.OrderStatus != q.OrderStatus {
+		return false
+	}
+	if q.PaymentStatus != "" && order.PaymentStatus != q.PaymentStatus {
+		return false
+	}
+	if q.ShippingStatus != "" && order.ShippingStatus != q.ShippingStatus {
+		return false
+	}
+	if q.StoreID != 0 && order.StoreID != q.StoreID {
+		return false
+	}
+	if q.CreatedAtMin != nil || q.CreatedAtMax != nil {
+		if order.CreatedOnUtc == nil {
+			return false
+		}
+		if q.CreatedAtMin != nil && order.CreatedOnUtc.Before(*q.CreatedAtMin) {
+			return false
+		}
+		if q.CreatedAtMax != nil && order.CreatedOnUtc.After(*q.CreatedAtMax) {
+			return false
+		}
+	}
+	return true
+}
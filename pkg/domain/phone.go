@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// countryCallingCodes maps ISO 3166-1 alpha-2 codes to E.164 calling codes for
+// the countries the stores ship to.
+var countryCallingCodes = map[string]string{
+	"AR": "54", "BO": "591", "BR": "55", "CA": "1", "CL": "56", "CO": "57",
+	"CR": "506", "CU": "53", "DE": "49", "DO": "1", "EC": "593", "ES": "34",
+	"FR": "33", "GB": "44", "GT": "502", "HN": "504", "IT": "39", "MX": "52",
+	"NI": "505", "PA": "507", "PE": "51", "PR": "1", "PT": "351", "PY": "595",
+	"SV": "503", "US": "1", "UY": "598", "VE": "58",
+}
+
+// CountryCallingCode returns the E.164 calling code of an ISO alpha-2 country.
+func CountryCallingCode(countryISO string) (string, bool) {
+	code, ok := countryCallingCodes[strings.ToUpper(strings.TrimSpace(countryISO))]
+	return code, ok
+}
+
+// NormalizePhoneE164 formats a phone number as E.164, e.g. "+5213312345678".
+// Numbers written with "+" or "00" keep their country code; national numbers
+// get the calling code of defaultCountry after dropping a leading trunk "0".
+func NormalizePhoneE164(phone, defaultCountry string) (string, error) {
+	trimmed := strings.TrimSpace(phone)
+	if trimmed == "" {
+		return "", nil
+	}
+
+	for _, marker := range []string{"ext", "x", "#"} {
+		if i := strings.Index(strings.ToLower(trimmed), marker); i > 0 {
+			trimmed = trimmed[:i]
+		}
+	}
+
+	international := strings.HasPrefix(trimmed, "+")
+	digits := onlyDigits(trimmed)
+	if strings.HasPrefix(digits, "00") && !international {
+		digits = digits[2:]
+		international = true
+	}
+
+	if !international {
+		code, ok := CountryCallingCode(defaultCountry)
+		if !ok {
+			return "", fmt.Errorf("%w: %q has no country code and no known default country", ErrInvalidPhone, phone)
+		}
+		digits = code + strings.TrimLeft(digits, "0")
+	}
+
+	if len(digits) < 8 || len(digits) > 15 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPhone, phone)
+	}
+	return "+" + digits, nil
+}
+
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
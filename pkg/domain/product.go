@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// EcommerceItemSource is the Source of items mapped from ecommerce products.
+const EcommerceItemSource = "kivio ecommerce"
+
+type ProductImage struct {
+	ID       int    `json:"id,omitempty"`
+	Src      string `json:"src"`
+	Position int    `json:"position,omitempty"`
+}
+
+// Product is the full ecommerce product as returned by /api/products/{id}.
+type Product struct {
+	ID                   int            `json:"id"`
+	Name                 string         `json:"name"`
+	ShortDescription     string         `json:"short_description"`
+	FullDescription      string         `json:"full_description"`
+	SKU                  string         `json:"sku"`
+	Price                float64        `json:"price"`
+	OldPrice             float64        `json:"old_price"`
+	StockQuantity        int64          `json:"stock_quantity"`
+	Published            bool           `json:"published"`
+	Weight               float64        `json:"weight"`
+	Length               float64        `json:"length"`
+	Width                float64        `json:"width"`
+	Height               float64        `json:"height"`
+	Images               []ProductImage `json:"images"`
+	CategoryIDs          []int          `json:"category_ids"`
+	ManufacturerIDs      []int          `json:"manufacturer_ids"`
+	Tags                 []string       `json:"tags"`
+	OrderMinimumQuantity int            `json:"order_minimum_quantity"`
+	OrderMaximumQuantity int            `json:"order_maximum_quantity"`
+	AllowedQuantities    string         `json:"allowed_quantities"`
+	CreatedOnUtc         *time.Time     `json:"created_on_utc,omitempty"`
+	UpdatedOnUtc         *time.Time     `json:"updated_on_utc,omitempty"`
+}
+
+func (p *Product) Ref() ItemRef {
+	return NewEcommerceItemRef(p.ID)
+}
+
+// SortedImages returns the images ordered by position, keeping the API order
+// for equal positions.
+func (p *Product) SortedImages() []ProductImage {
+	images := make([]ProductImage, len(p.Images))
+	copy(images, p.Images)
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].Position < images[j].Position
+	})
+	return images
+}
+
+func (p *Product) MainImageURL() string {
+	images := p.SortedImages()
+	if len(images) == 0 {
+		return ""
+	}
+	return images[0].Src
+}
+
+// firstImageURL is the image the item methods have always used: the first one
+// in API order, regardless of position.
+func (p *Product) firstImageURL() string {
+	if len(p.Images) == 0 {
+		return ""
+	}
+	return p.Images[0].Src
+}
+
+func (p *Product) ToItem() Item {
+	ref := p.Ref().String()
+	return Item{
+		ItemId:        ref,
+		Name:          p.Name,
+		Description:   p.ShortDescription,
+		ExternalId:    ref,
+		Url:           p.firstImageURL(),
+		Source:        EcommerceItemSource,
+		StockQuantity: p.StockQuantity,
+	}
+}
+
+func (p *Product) ToItemDetails() ItemDetails {
+	return ItemDetails{
+		Item:                 p.ToItem(),
+		Availability:         int(p.StockQuantity),
+		Price:                p.Price,
+		Published:            p.Published,
+		OrderMinimumQuantity: p.OrderMinimumQuantity,
+		OrderMaximumQuantity: p.OrderMaximumQuantity,
+		AllowedQuantities:    ParseAllowedQuantities(p.AllowedQuantities),
+	}
+}
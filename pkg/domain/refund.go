@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrRefundNotSupported  = errors.New("the store does not support refunds")
+	ErrInvalidRefundAmount = errors.New("invalid refund amount")
+	ErrOrderNotRefundable  = errors.New("order is not refundable")
+)
+
+type RefundOptions struct {
+	Reason  string
+	Restock bool
+}
+
+type RefundRequest struct {
+	Amount    float64 `json:"amount"`
+	IsPartial bool    `json:"is_partial"`
+	Reason    string  `json:"reason,omitempty"`
+	Restock   bool    `json:"restock"`
+}
+
+type RefundResult struct {
+	OrderID         int
+	RequestedAmount float64
+	RefundedAmount  float64
+	OrderTotal      float64
+	PaymentStatus   PaymentStatus
+}
+
+func (o *Order) RefundableAmount() float64 {
+	return math.Max(0, math.Round((o.OrderTotal-o.RefundedAmount)*100)/100)
+}
+
+// NewRefundRequest validates a refund of the given amount against the order.
+// An amount of zero refunds everything that has not been refunded yet.
+func (o *Order) NewRefundRequest(amount float64, opts RefundOptions) (RefundRequest, error) {
+	if o.PaymentStatus != PaymentStatusPaid && o.PaymentStatus != PaymentStatusPartiallyRefunded {
+		return RefundRequest{}, fmt.Errorf("%w: order %d payment status is %s", ErrOrderNotRefundable, o.ID, o.PaymentStatus)
+	}
+
+	refundable := o.RefundableAmount()
+	if amount == 0 {
+		amount = refundable
+	}
+	if amount <= 0 {
+		return RefundRequest{}, fmt.Errorf("%w: %.2f must be positive", ErrInvalidRefundAmount, amount)
+	}
+	if amount > refundable {
+		return RefundRequest{}, fmt.Errorf("%w: %.2f exceeds the refundable %.2f of order %d", ErrInvalidRefundAmount, amount, refundable, o.ID)
+	}
+
+	return RefundRequest{
+		Amount:    amount,
+		IsPartial: amount < refundable,
+		Reason:    opts.Reason,
+		Restock:   opts.Restock,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type Shipment struct {
+	ID              int            `json:"id,omitempty"`
+	OrderID         int            `json:"order_id"`
+	TrackingNumber  string         `json:"tracking_number,omitempty"`
+	Carrier         string         `json:"carrier,omitempty"`
+	ShippedDateUtc  *time.Time     `json:"shipped_date_utc,omitempty"`
+	DeliveryDateUtc *time.Time     `json:"delivery_date_utc,omitempty"`
+	CreatedOnUtc    *time.Time     `json:"created_on_utc,omitempty"`
+	Items           []ShipmentItem `json:"shipment_items,omitempty"`
+}
+
+type ShipmentItem struct {
+	ID          int `json:"id,omitempty"`
+	OrderItemID int `json:"order_item_id"`
+	Quantity    int `json:"quantity"`
+}
+
+func (s *Shipment) IsDelivered() bool {
+	return s.DeliveryDateUtc != nil
+}
+
+// ShippedQuantities sums the shipped quantity of every order item across the
+// given shipments.
+func ShippedQuantities(shipments []Shipment) map[int]int {
+	shipped := make(map[int]int)
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			shipped[item.OrderItemID] += item.Quantity
+		}
+	}
+	return shipped
+}
+
+// NewShipment builds a shipment for the order, given its existing shipments.
+// Without explicit items every order item is shipped with its remaining
+// quantity; explicit items must reference order items, and their quantities,
+// summed per order item, must not exceed what remains to be shipped.
+func (o *Order) NewShipment(trackingNumber, carrier string, items []ShipmentItem, existing []Shipment) (*Shipment, error) {
+	if o.OrderStatus == OrderStatusCancelled {
+		return nil, fmt.Errorf("cannot ship cancelled order %d", o.ID)
+	}
+
+	shipped := ShippedQuantities(existing)
+
+	if len(items) == 0 {
+		for _, orderItem := range o.OrderItems {
+			if remaining := orderItem.Quantity - shipped[orderItem.ID]; remaining > 0 {
+				items = append(items, ShipmentItem{OrderItemID: orderItem.ID, Quantity: remaining})
+			}
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("order %d has no items left to ship", o.ID)
+		}
+	}
+
+	requested := make(map[int]int)
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid quantity %d for order item %d", item.Quantity, item.OrderItemID)
+		}
+		requested[item.OrderItemID] += item.Quantity
+	}
+
+	for orderItemID, quantity := range requested {
+		orderItem, ok := o.findItem(orderItemID)
+		if !ok {
+			return nil, fmt.Errorf("order %d has no item %d", o.ID, orderItemID)
+		}
+		if remaining := orderItem.Quantity - shipped[orderItemID]; quantity > remaining {
+			return nil, fmt.Errorf("invalid quantity %d for order item %d (ordered %d, already shipped %d)",
+				quantity, orderItemID, orderItem.Quantity, shipped[orderItemID])
+		}
+	}
+
+	now := time.Now().UTC()
+	return &Shipment{
+		OrderID:        o.ID,
+		TrackingNumber: trackingNumber,
+		Carrier:        carrier,
+		ShippedDateUtc: &now,
+		Items:          items,
+	}, nil
+}
+
+func (o *Order) findItem(orderItemID int) (*OrderItem, bool) {
+	for i := range o.OrderItems {
+		if o.OrderItems[i].ID == orderItemID {
+			return &o.OrderItems[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	ShoppingCartTypeCart     = "ShoppingCart"
+	ShoppingCartTypeWishlist = "Wishlist"
+)
+
+type ShoppingCartItem struct {
+	ID                   int     `json:"id,omitempty"`
+	CustomerID           int     `json:"customer_id"`
+	ProductID            int     `json:"product_id"`
+	Quantity             int     `json:"quantity"`
+	CustomerEnteredPrice float64 `json:"customer_entered_price,omitempty"`
+	ShoppingCartType     string  `json:"shopping_cart_type"`
+	StoreID              int     `json:"store_id,omitempty"`
+}
+
+type CartItemInput struct {
+	CustomerID  int
+	ItemRef     ItemRef
+	Quantity    int
+	CustomPrice float64
+	CartType    string
+	StoreID     int
+	// ReservedQuantity is the stock already reserved for this line, e.g. with
+	// StockService.ReserveStock. It counts as available in the stock check.
+	ReservedQuantity int
+}
+
+// NormalizeShoppingCartType defaults an empty cart type to the shopping cart
+// and rejects types other than the shopping cart and the wishlist.
+func NormalizeShoppingCartType(cartType string) (string, error) {
+	switch cartType {
+	case "":
+		return ShoppingCartTypeCart, nil
+	case ShoppingCartTypeCart, ShoppingCartTypeWishlist:
+		return cartType, nil
+	default:
+		return "", fmt.Errorf("unsupported shopping cart type %q", cartType)
+	}
+}
+
+func (in CartItemInput) ToShoppingCartItem() (ShoppingCartItem, error) {
+	if in.CustomerID <= 0 {
+		return ShoppingCartItem{}, fmt.Errorf("customer ID must be positive, got %d", in.CustomerID)
+	}
+	if in.Quantity <= 0 {
+		return ShoppingCartItem{}, fmt.Errorf("quantity must be positive, got %d", in.Quantity)
+	}
+	if in.ReservedQuantity < 0 {
+		return ShoppingCartItem{}, fmt.Errorf("reserved quantity cannot be negative, got %d", in.ReservedQuantity)
+	}
+	if in.CustomPrice < 0 {
+		return ShoppingCartItem{}, fmt.Errorf("custom price cannot be negative, got %.2f", in.CustomPrice)
+	}
+	if err := in.ItemRef.Validate(); err != nil {
+		return ShoppingCartItem{}, err
+	}
+	productID, ok := in.ItemRef.NumericID()
+	if !ok {
+		return ShoppingCartItem{}, fmt.Errorf("%w: %s does not reference an ecommerce product", ErrInvalidItemRef, in.ItemRef)
+	}
+	cartType, err := NormalizeShoppingCartType(in.CartType)
+	if err != nil {
+		return ShoppingCartItem{}, err
+	}
+
+	return ShoppingCartItem{
+		CustomerID:           in.CustomerID,
+		ProductID:            productID,
+		Quantity:             in.Quantity,
+		CustomerEnteredPrice: in.CustomPrice,
+		ShoppingCartType:     cartType,
+		StoreID:              in.StoreID,
+	}, nil
+}
+
+// DecodeShoppingCartItem reads a shopping cart item from a raw create payload,
+// either wrapped in a "shopping_cart_item" object or bare.
+func DecodeShoppingCartItem(data []byte) (ShoppingCartItem, error) {
+	var envelope struct {
+		Item *ShoppingCartItem `json:"shopping_cart_item"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return ShoppingCartItem{}, fmt.Errorf("invalid shopping cart item payload: %w", err)
+	}
+	if envelope.Item != nil {
+		return *envelope.Item, nil
+	}
+
+	var item ShoppingCartItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return ShoppingCartItem{}, fmt.Errorf("invalid shopping cart item payload: %w", err)
+	}
+	return item, nil
+}
+
+// Validate applies the same checks as CartItemInput to an item that did not
+// come from one.
+func (i ShoppingCartItem) Validate() error {
+	if i.CustomerID <= 0 {
+		return fmt.Errorf("customer ID must be positive, got %d", i.CustomerID)
+	}
+	if i.ProductID <= 0 {
+		return fmt.Errorf("product ID must be positive, got %d", i.ProductID)
+	}
+	if i.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive, got %d", i.Quantity)
+	}
+	if i.CustomerEnteredPrice < 0 {
+		return fmt.Errorf("custom price cannot be negative, got %.2f", i.CustomerEnteredPrice)
+	}
+	_, err := NormalizeShoppingCartType(i.ShoppingCartType)
+	return err
+}
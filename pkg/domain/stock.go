@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+type StockReservationStatus string
+
+const (
+	StockReservationReserved  StockReservationStatus = "reserved"
+	StockReservationReleased  StockReservationStatus = "released"
+	StockReservationCommitted StockReservationStatus = "committed"
+)
+
+type StockReservation struct {
+	ReservationID string
+	ItemRef       ItemRef
+	Quantity      int64
+	Status        StockReservationStatus
+	CreatedAt     time.Time
+	ReleasedAt    *time.Time
+	CommittedAt   *time.Time
+}
+
+func (r *StockReservation) IsActive() bool {
+	return r.Status == StockReservationReserved
+}
+
+type StockLedgerEntry struct {
+	ItemRef   ItemRef
+	Before    int64
+	After     int64
+	Delta     int64
+	Reason    string
+	Actor     string
+	Timestamp time.Time
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+func (r *ecommerceRepository) ListCustomerAddresses(baseUrl, apiKey string, customerID int) ([]domain.Address, error) {
+	respBody, err := r.client.GetCustomerAddresses(baseUrl, apiKey, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Addresses []domain.Address `json:"addresses"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding addresses response: %w", err)
+	}
+	return resp.Addresses, nil
+}
+
+// UpdateCustomerAddress returns the updated address, or the submitted one when
+// the server answers without a body.
+func (r *ecommerceRepository) UpdateCustomerAddress(baseUrl, apiKey string, customerID int, address domain.Address) (*domain.Address, error) {
+	payload, err := encodeEntity("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.client.UpdateCustomerAddress(baseUrl, apiKey, customerID, address.ID, payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(respBody) == 0 {
+		return &address, nil
+	}
+
+	var updated domain.Address
+	if err := decodeEntity(respBody, "address", "addresses", &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *ecommerceRepository) UpdateCustomerAddressFields(baseUrl, apiKey string, customerID, addressID int, fields map[string]interface{}) error {
+	payload, err := encodeEntity("address", fields)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.UpdateCustomerAddress(baseUrl, apiKey, customerID, addressID, payload)
+	return err
+}
+
+func (r *ecommerceRepository) DeleteCustomerAddress(baseUrl, apiKey string, customerID, addressID int) error {
+	return r.client.DeleteCustomerAddress(baseUrl, apiKey, customerID, addressID)
+}
+
+func (r *ecommerceRepository) SetDefaultCustomerAddress(baseUrl, apiKey string, customerID, addressID int, kind domain.AddressKind) error {
+	if err := kind.Validate(); err != nil {
+		return err
+	}
+
+	payload, err := encodeEntity("customer", map[string]interface{}{
+		string(kind) + "_address_id": addressID,
+	})
+	if err != nil {
+		return err
+	}
+	return r.client.UpdateCustomer(baseUrl, apiKey, customerID, payload)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+func (r *ecommerceRepository) AddShoppingCartItem(baseUrl, apiKey string, item domain.ShoppingCartItem) (*domain.ShoppingCartItem, error) {
+	payload, err := encodeEntity("shopping_cart_item", item)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.client.CreateShoppingCartItem(baseUrl, apiKey, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var created domain.ShoppingCartItem
+	if err := decodeEntity(respBody, "shopping_cart_item", "shopping_carts", &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *ecommerceRepository) ListShoppingCartItems(baseUrl, apiKey string, customerID int, cartType string) ([]domain.ShoppingCartItem, error) {
+	respBody, err := r.client.GetShoppingCartItems(baseUrl, apiKey, customerID, cartType)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		ShoppingCarts []domain.ShoppingCartItem `json:"shopping_carts"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding shopping cart response: %w", err)
+	}
+	return resp.ShoppingCarts, nil
+}
+
+func (r *ecommerceRepository) UpdateShoppingCartItemQuantity(baseUrl, apiKey string, cartItemID, quantity int) (*domain.ShoppingCartItem, error) {
+	payload, err := encodeEntity("shopping_cart_item", map[string]interface{}{
+		"quantity": quantity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.client.UpdateShoppingCartItem(baseUrl, apiKey, cartItemID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := domain.ShoppingCartItem{ID: cartItemID, Quantity: quantity}
+	if len(respBody) == 0 {
+		return &updated, nil
+	}
+	if err := decodeEntity(respBody, "shopping_cart_item", "shopping_carts", &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *ecommerceRepository) DeleteShoppingCartItem(baseUrl, apiKey string, cartItemID int) error {
+	return r.client.DeleteShoppingCartItem(baseUrl, apiKey, cartItemID)
+}
+
+func (r *ecommerceRepository) ClearShoppingCart(baseUrl, apiKey string, customerID int, cartType string) error {
+	return r.client.ClearShoppingCart(baseUrl, apiKey, customerID, cartType)
+}
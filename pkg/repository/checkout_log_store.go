@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+// CheckoutLogStore persists checkout saga logs so an interrupted checkout can
+// be resumed. GetCheckoutLog returns nil without error when no log exists.
+type CheckoutLogStore interface {
+	GetCheckoutLog(ctx context.Context, checkoutID string) (*domain.CheckoutLog, error)
+	SaveCheckoutLog(ctx context.Context, log *domain.CheckoutLog) error
+}
+
+type inMemoryCheckoutLogStore struct {
+	mu   sync.Mutex
+	logs map[string]domain.CheckoutLog
+}
+
+func NewInMemoryCheckoutLogStore() CheckoutLogStore {
+	return &inMemoryCheckoutLogStore{
+		logs: make(map[string]domain.CheckoutLog),
+	}
+}
+
+func (s *inMemoryCheckoutLogStore) GetCheckoutLog(ctx context.Context, checkoutID string) (*domain.CheckoutLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log, ok := s.logs[checkoutID]
+	if !ok {
+		return nil, nil
+	}
+	log.CompletedSteps = append([]domain.CheckoutStep(nil), log.CompletedSteps...)
+	return &log, nil
+}
+
+func (s *inMemoryCheckoutLogStore) SaveCheckoutLog(ctx context.Context, log *domain.CheckoutLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *log
+	stored.CompletedSteps = append([]domain.CheckoutStep(nil), log.CompletedSteps...)
+	s.logs[log.CheckoutID] = stored
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+func (r *ecommerceRepository) CreateCustomerFromInput(baseUrl, apiKey string, input domain.CustomerInput) (*domain.Customer, error) {
+	payload, err := encodeEntity("customer", input)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.client.CreateCustomer(baseUrl, apiKey, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var customer domain.Customer
+	if err := decodeEntity(respBody, "customer", "customers", &customer); err != nil {
+		return nil, err
+	}
+	if customer.ID == 0 {
+		return nil, fmt.Errorf("created customer has no ID")
+	}
+	return &customer, nil
+}
+
+func (r *ecommerceRepository) DeleteCustomer(baseUrl, apiKey string, customerID int) error {
+	return r.client.DeleteCustomer(baseUrl, apiKey, customerID)
+}
+
+func (r *ecommerceRepository) AddCustomerBillingAddress(baseUrl, apiKey string, customerID int, address domain.Address) (*domain.Address, error) {
+	payload, err := encodeEntity("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.client.CreateBillingAddress(baseUrl, apiKey, customerID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var created domain.Address
+	if err := decodeEntity(respBody, "address", "addresses", &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *ecommerceRepository) AddCustomerShippingAddress(baseUrl, apiKey string, customerID int, address domain.Address) (*domain.Address, error) {
+	payload, err := encodeEntity("address", address)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.client.CreateShippingAddress(baseUrl, apiKey, customerID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var created domain.Address
+	if err := decodeEntity(respBody, "address", "addresses", &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// PlaceOrder creates a typed order. Orders carrying the idempotency key custom
+// value go through CreateOrderIdempotent so retries return the original order.
+func (r *ecommerceRepository) PlaceOrder(baseUrl, apiKey string, order domain.Order) (*domain.Order, error) {
+	payload, err := encodeEntity("order", order)
+	if err != nil {
+		return nil, err
+	}
+
+	var respBody []byte
+	if key := order.CustomValue(domain.OrderIdempotencyKeyAttribute); key != "" {
+		respBody, err = r.CreateOrderIdempotent(baseUrl, apiKey, payload, key)
+	} else {
+		respBody, err = r.client.CreateOrder(baseUrl, apiKey, payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var created domain.Order
+	if err := decodeEntity(respBody, "order", "orders", &created); err != nil {
+		return nil, err
+	}
+	if created.ID == 0 {
+		return nil, fmt.Errorf("created order has no ID")
+	}
+	return &created, nil
+}
+
+func (r *ecommerceRepository) GetOrder(baseUrl, apiKey string, orderID int) (*domain.Order, error) {
+	respBody, err := r.client.GetOrderByID(baseUrl, apiKey, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var order domain.Order
+	if err := decodeEntity(respBody, "order", "orders", &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *ecommerceRepository) UpdateOrderItem(baseUrl, apiKey string, orderID int, item domain.OrderItem) error {
+	payload, err := encodeEntity("order_item", item)
+	if err != nil {
+		return err
+	}
+	return r.client.UpdateOrderItemPrice(baseUrl, apiKey, orderID, item.ID, payload)
+}
+
+func (r *ecommerceRepository) DeleteOrder(baseUrl, apiKey string, orderID int) error {
+	return r.client.DeleteOrder(baseUrl, apiKey, orderID)
+}
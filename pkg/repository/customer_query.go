@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+const defaultCustomerPageLimit = 100
+
+func (r *ecommerceRepository) ListCustomerRoles(baseUrl, apiKey string) ([]domain.CustomerRole, error) {
+	respBody, err := r.client.GetCustomerRoles(baseUrl, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		CustomerRoles []domain.CustomerRole `json:"customer_roles"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding customer roles response: %w", err)
+	}
+	return resp.CustomerRoles, nil
+}
+
+func (r *ecommerceRepository) resolveCustomerRoleID(baseUrl, apiKey, systemName string) (int, error) {
+	roles, err := r.ListCustomerRoles(baseUrl, apiKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list customer roles: %w", err)
+	}
+	role, ok := domain.FindCustomerRole(roles, systemName)
+	if !ok {
+		return 0, fmt.Errorf("customer role %q not found", systemName)
+	}
+	return role.ID, nil
+}
+
+// registeredRoleID looks up the Registered role of the store and falls back to
+// the default installation's ID when the roles cannot be listed.
+func (r *ecommerceRepository) registeredRoleID(baseUrl, apiKey string) int {
+	roleID, err := r.resolveCustomerRoleID(baseUrl, apiKey, domain.CustomerRoleRegistered)
+	if err != nil {
+		fmt.Printf("[CUSTOMER_ROLES] No se pudo resolver el rol %s, usando RoleId=%d: %v\n",
+			domain.CustomerRoleRegistered, domain.DefaultRegisteredRoleID, err)
+		return domain.DefaultRegisteredRoleID
+	}
+	return roleID
+}
+
+func (r *ecommerceRepository) ListCustomers(baseUrl, apiKey string, query domain.CustomerQuery) ([]domain.Customer, error) {
+	roleID := query.RoleID
+	if roleID == 0 {
+		if name := query.RoleName(); name == domain.CustomerRoleRegistered {
+			roleID = r.registeredRoleID(baseUrl, apiKey)
+		} else if name != "" {
+			var err error
+			if roleID, err = r.resolveCustomerRoleID(baseUrl, apiKey, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultCustomerPageLimit
+	}
+
+	page := query.Page
+	allPages := page <= 0
+	if allPages {
+		page = 1
+	}
+
+	var customers []domain.Customer
+	for {
+		params := customerQueryParams(query, roleID)
+		params.Set("Page", strconv.Itoa(page))
+		params.Set("Limit", strconv.Itoa(limit))
+
+		respBody, err := r.client.ListCustomers(baseUrl, apiKey, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			Customers []domain.Customer `json:"customers"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return nil, fmt.Errorf("error decoding customers response: %w", err)
+		}
+
+		for i := range resp.Customers {
+			if query.Matches(&resp.Customers[i], roleID) {
+				customers = append(customers, resp.Customers[i])
+			}
+		}
+
+		if !allPages || len(resp.Customers) < limit {
+			break
+		}
+
+		page++
+	}
+
+	return customers, nil
+}
+
+func customerQueryParams(query domain.CustomerQuery, roleID int) url.Values {
+	params := url.Values{}
+	if roleID != 0 {
+		params.Set("RoleId", strconv.Itoa(roleID))
+	}
+	if query.Email != "" {
+		params.Set("Email", domain.NormalizeEmail(query.Email))
+	}
+	if query.CreatedAtMin != nil {
+		params.Set("CreatedAtMin", query.CreatedAtMin.UTC().Format(time.RFC3339))
+	}
+	if query.CreatedAtMax != nil {
+		params.Set("CreatedAtMax", query.CreatedAtMax.UTC().Format(time.RFC3339))
+	}
+	return params
+}
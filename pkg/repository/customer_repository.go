@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+const customerEmailSearchLimit = 50
+
+// FindCustomerByEmail asks the API to filter customers by email. Stores that
+// ignore the filter return unrelated customers; in that case it falls back to
+// scanning every customer page of every role, guests included. Emails are
+// compared case-insensitively.
+func (r *ecommerceRepository) FindCustomerByEmail(baseUrl, apiKey, email string) (*domain.Customer, error) {
+	email = domain.NormalizeEmail(email)
+	if email == "" {
+		return nil, fmt.Errorf("email cannot be empty")
+	}
+
+	respBody, err := r.client.SearchCustomersByEmail(baseUrl, apiKey, email, customerEmailSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Customers []domain.Customer `json:"customers"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	filtered := true
+	for i := range resp.Customers {
+		if !resp.Customers[i].HasEmail(email) {
+			filtered = false
+			break
+		}
+	}
+
+	if filtered {
+		if len(resp.Customers) == 0 {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCustomerNotFound, email)
+		}
+		return &resp.Customers[0], nil
+	}
+
+	fmt.Printf("[FIND_CUSTOMER_BY_EMAIL] El servidor no filtró por email, recorriendo todos los clientes\n")
+
+	customers, err := r.ListCustomers(baseUrl, apiKey, domain.CustomerQuery{Email: email})
+	if err != nil {
+		return nil, err
+	}
+	for i := range customers {
+		if customers[i].HasEmail(email) {
+			return &customers[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", domain.ErrCustomerNotFound, email)
+}
+
+func (r *ecommerceRepository) UpdateCustomerFields(baseUrl, apiKey string, customerID int, fields map[string]interface{}) error {
+	payload, err := encodeEntity("customer", fields)
+	if err != nil {
+		return err
+	}
+	return r.client.UpdateCustomer(baseUrl, apiKey, customerID, payload)
+}
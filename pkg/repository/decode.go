@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// decodeEntity decodes a single entity from any of the envelopes returned by
+// the ecommerce API: {"<singular>": {...}}, {"<plural>": [{...}]} or the bare
+// object itself.
+func decodeEntity(body []byte, singular, plural string, out interface{}) error {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+
+	if raw, ok := envelope[singular]; ok {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return fmt.Errorf("error decoding %s: %w", singular, err)
+		}
+		return nil
+	}
+
+	if raw, ok := envelope[plural]; ok {
+		var list []json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return fmt.Errorf("error decoding %s: %w", plural, err)
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("%s not found in response", singular)
+		}
+		if err := json.Unmarshal(list[0], out); err != nil {
+			return fmt.Errorf("error decoding %s: %w", singular, err)
+		}
+		return nil
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error decoding %s: %w", singular, err)
+	}
+	return nil
+}
+
+func encodeEntity(key string, entity interface{}) ([]byte, error) {
+	payload, err := json.Marshal(map[string]interface{}{key: entity})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	return payload, nil
+}
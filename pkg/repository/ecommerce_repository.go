@@ -3,8 +3,8 @@ package repository
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/client"
 	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
@@ -16,6 +16,7 @@ type EcommerceRepository interface {
 	GetItemsRaw(baseUrl, apiKey string, page, limit int, publishedStatus bool) ([]byte, error)
 	GetItemByID(baseUrl, apiKey, itemId string) (*domain.Item, error)
 	GetItemByIDWithDetails(baseUrl, apiKey, itemId string) (*domain.ItemDetails, error)
+	GetProduct(baseUrl, apiKey, itemId string) (*domain.Product, error)
 	GetItemByIDRaw(baseUrl, apiKey, itemId string) ([]byte, error)
 	GetCustomers(baseUrl, apiKey string) ([]domain.Customer, error)
 	GetAllCustomers(baseUrl, apiKey string) ([]domain.Customer, error)
@@ -36,15 +37,53 @@ type EcommerceRepository interface {
 	UpdateOrderItemPrice(baseUrl, apiKey string, orderID, itemID int, orderItemData []byte) error
 	UpdateOrder(baseUrl, apiKey string, orderID int, orderData []byte) error
 	GetOrderByID(baseUrl, apiKey string, orderID int) ([]byte, error)
+	CreateCustomerFromInput(baseUrl, apiKey string, input domain.CustomerInput) (*domain.Customer, error)
+	DeleteCustomer(baseUrl, apiKey string, customerID int) error
+	UpdateCustomerFields(baseUrl, apiKey string, customerID int, fields map[string]interface{}) error
+	ListNewsletterSubscriptions(baseUrl, apiKey string) ([]domain.NewsletterSubscription, error)
+	AddCustomerBillingAddress(baseUrl, apiKey string, customerID int, address domain.Address) (*domain.Address, error)
+	AddCustomerShippingAddress(baseUrl, apiKey string, customerID int, address domain.Address) (*domain.Address, error)
+	ListCustomerAddresses(baseUrl, apiKey string, customerID int) ([]domain.Address, error)
+	UpdateCustomerAddress(baseUrl, apiKey string, customerID int, address domain.Address) (*domain.Address, error)
+	UpdateCustomerAddressFields(baseUrl, apiKey string, customerID, addressID int, fields map[string]interface{}) error
+	DeleteCustomerAddress(baseUrl, apiKey string, customerID, addressID int) error
+	SetDefaultCustomerAddress(baseUrl, apiKey string, customerID, addressID int, kind domain.AddressKind) error
+	AddShoppingCartItem(baseUrl, apiKey string, item domain.ShoppingCartItem) (*domain.ShoppingCartItem, error)
+	PlaceOrder(baseUrl, apiKey string, order domain.Order) (*domain.Order, error)
+	GetOrder(baseUrl, apiKey string, orderID int) (*domain.Order, error)
+	UpdateOrderItem(baseUrl, apiKey string, orderID int, item domain.OrderItem) error
+	DeleteOrder(baseUrl, apiKey string, orderID int) error
+	CreateOrderIdempotent(baseUrl, apiKey string, orderData []byte, idempotencyKey string) ([]byte, error)
+	FindOrderByIdempotencyKey(baseUrl, apiKey string, customerID int, idempotencyKey string) (*domain.Order, error)
+	FindCustomerByEmail(baseUrl, apiKey, email string) (*domain.Customer, error)
+	ListCustomers(baseUrl, apiKey string, query domain.CustomerQuery) ([]domain.Customer, error)
+	ListCustomerRoles(baseUrl, apiKey string) ([]domain.CustomerRole, error)
+	ListOrders(baseUrl, apiKey string, query domain.OrderQuery) ([]domain.Order, error)
+	UpdateOrderFields(baseUrl, apiKey string, orderID int, fields map[string]interface{}) error
+	RefundOrder(baseUrl, apiKey string, orderID int, refund domain.RefundRequest) error
+	CreateShipment(baseUrl, apiKey string, shipment domain.Shipment) (*domain.Shipment, error)
+	ListOrderShipments(baseUrl, apiKey string, orderID int) ([]domain.Shipment, error)
+	MarkShipmentDelivered(baseUrl, apiKey string, shipmentID int, deliveredAt time.Time) error
+	AddOrderNote(baseUrl, apiKey string, note domain.OrderNote) (*domain.OrderNote, error)
+	ListOrderNotes(baseUrl, apiKey string, orderID int) ([]domain.OrderNote, error)
+	ListShoppingCartItems(baseUrl, apiKey string, customerID int, cartType string) ([]domain.ShoppingCartItem, error)
+	UpdateShoppingCartItemQuantity(baseUrl, apiKey string, cartItemID, quantity int) (*domain.ShoppingCartItem, error)
+	DeleteShoppingCartItem(baseUrl, apiKey string, cartItemID int) error
+	ClearShoppingCart(baseUrl, apiKey string, customerID int, cartType string) error
+	ListCountries(baseUrl, apiKey string) ([]domain.Country, error)
+	ListStates(baseUrl, apiKey string, countryID int) ([]domain.StateProvince, error)
+	ResolveAddressRegion(baseUrl, apiKey string, address domain.Address) (domain.Address, error)
 }
 
 type ecommerceRepository struct {
 	client client.EcommerceClient
+	geo    *geoCache
 }
 
 func NewEcommerceRepository() EcommerceRepository {
 	return &ecommerceRepository{
 		client: client.NewEcommerceClient(),
+		geo:    newGeoCache(geoCacheTTL),
 	}
 }
 
@@ -58,74 +97,23 @@ func (r *ecommerceRepository) GetItems(baseUrl, apiKey string, page, limit int)
 		return nil, err
 	}
 
-	type Product struct {
-		ID            int    `json:"id"`
-		Name          string `json:"name"`
-		Description   string `json:"short_description"`
-		StockQuantity int64  `json:"stock_quantity"`
-		Images        []struct {
-			Src string `json:"src"`
-		} `json:"images"`
-		Published bool `json:"published"`
-	}
-
-	type ApiResponse struct {
-		Products []Product `json:"products"`
-		Total    int       `json:"total"`
-		Pages    int       `json:"pages"`
-	}
-
-	var apiResponse ApiResponse
-	if err := json.Unmarshal(respBody, &apiResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	products, err := decodeProducts(respBody)
+	if err != nil {
+		return nil, err
 	}
 
 	var items []domain.Item
-	for _, product := range apiResponse.Products {
-		if !product.Published {
+	for i := range products {
+		if !isSellable(&products[i]) {
 			continue
 		}
-
-		if product.StockQuantity <= 0 {
-			continue
-		}
-
-		var imageURL string
-		if len(product.Images) > 0 {
-			imageURL = product.Images[0].Src
-		}
-
-		item := domain.Item{
-			ItemId:      fmt.Sprintf("kivio-ecommerce∼%d", product.ID),
-			Name:        product.Name,
-			Description: product.Description,
-			ExternalId:  fmt.Sprintf("kivio-ecommerce∼%d", product.ID),
-			Url:         imageURL,
-		}
-		items = append(items, item)
+		items = append(items, products[i].ToItem())
 	}
 
 	return items, nil
 }
 
 func (r *ecommerceRepository) GetItemsWithLastItem(baseUrl, apiKey string, lastItemID string, limit int, filters map[string]string) ([]domain.Item, string, error) {
-	type Product struct {
-		ID            int    `json:"id"`
-		Name          string `json:"name"`
-		Description   string `json:"short_description"`
-		StockQuantity int64  `json:"stock_quantity"`
-		Images        []struct {
-			Src string `json:"src"`
-		} `json:"images"`
-		Published bool `json:"published"`
-	}
-
-	type ApiResponse struct {
-		Products []Product `json:"products"`
-		Total    int       `json:"total"`
-		Pages    int       `json:"pages"`
-	}
-
 	var items []domain.Item
 	currentPage := 1
 	maxPages := 100
@@ -138,21 +126,22 @@ func (r *ecommerceRepository) GetItemsWithLastItem(baseUrl, apiKey string, lastI
 			return nil, "", err
 		}
 
-		var apiResponse ApiResponse
-		if err := json.Unmarshal(respBody, &apiResponse); err != nil {
-			return nil, "", fmt.Errorf("failed to unmarshal items: %w", err)
+		products, err := decodeProducts(respBody)
+		if err != nil {
+			return nil, "", err
 		}
 
-		if len(apiResponse.Products) == 0 {
+		if len(products) == 0 {
 			break
 		}
 
-		for _, product := range apiResponse.Products {
+		for i := range products {
+			product := &products[i]
 			if !product.Published {
 				continue
 			}
 
-			productID := fmt.Sprintf("kivio-ecommerce∼%d", product.ID)
+			productID := product.Ref().String()
 
 			if !foundCursor {
 				if productID == lastItemID {
@@ -165,20 +154,7 @@ func (r *ecommerceRepository) GetItemsWithLastItem(baseUrl, apiKey string, lastI
 				break
 			}
 
-			var imageURL string
-			if len(product.Images) > 0 {
-				imageURL = product.Images[0].Src
-			}
-
-			item := domain.Item{
-				ItemId:        productID,
-				Name:          product.Name,
-				Description:   product.Description,
-				ExternalId:    productID,
-				Url:           imageURL,
-				StockQuantity: product.StockQuantity,
-			}
-			items = append(items, item)
+			items = append(items, product.ToItem())
 
 			nextItemID = productID
 		}
@@ -187,7 +163,7 @@ func (r *ecommerceRepository) GetItemsWithLastItem(baseUrl, apiKey string, lastI
 			break
 		}
 
-		if len(apiResponse.Products) < limit {
+		if len(products) < limit {
 			break
 		}
 
@@ -210,119 +186,45 @@ func (r *ecommerceRepository) CountEcommerceItems(baseUrl, apiKey string, filter
 }
 
 func (r *ecommerceRepository) GetItemByID(baseUrl, apiKey, itemId string) (*domain.Item, error) {
-	itemId = strings.TrimPrefix(itemId, "kivio-ecommerce∼")
-	respBody, err := r.client.GetItemByID(baseUrl, apiKey, itemId)
+	product, err := r.GetProduct(baseUrl, apiKey, itemId)
 	if err != nil {
 		return nil, err
 	}
 
-	type Image struct {
-		Src string `json:"src"`
-	}
-
-	type Product struct {
-		ID               int     `json:"id"`
-		Name             string  `json:"name"`
-		ShortDescription string  `json:"short_description"`
-		FullDescription  string  `json:"full_description"`
-		Price            float64 `json:"price"`
-		Images           []Image `json:"images"`
-		SKU              string  `json:"sku"`
-	}
-
-	type ApiResponse struct {
-		Products []Product `json:"products"`
-	}
-
-	var apiResponse ApiResponse
-	if err := json.Unmarshal(respBody, &apiResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if len(apiResponse.Products) == 0 {
-		return nil, fmt.Errorf("product not found")
-	}
-
-	product := apiResponse.Products[0]
-
-	var imageURL string
-	if len(product.Images) > 0 {
-		imageURL = product.Images[0].Src
-	}
-
-	item := &domain.Item{
-		ItemId:      fmt.Sprintf("kivio-ecommerce∼%d", product.ID),
-		Name:        product.Name,
-		Description: product.ShortDescription,
-		ExternalId:  product.SKU,
-		Source:      "kivio ecommerce",
-		Url:         imageURL,
-	}
-
-	return item, nil
+	item := product.ToItem()
+	return &item, nil
 }
 
 func (r *ecommerceRepository) GetItemByIDWithDetails(baseUrl, apiKey, itemId string) (*domain.ItemDetails, error) {
-	itemId = strings.TrimPrefix(itemId, "kivio-ecommerce∼")
-	respBody, err := r.client.GetItemByID(baseUrl, apiKey, itemId)
+	product, err := r.GetProduct(baseUrl, apiKey, itemId)
 	if err != nil {
 		return nil, err
 	}
 
-	type Image struct {
-		Src string `json:"src"`
-	}
-
-	type Product struct {
-		ID               int     `json:"id"`
-		Name             string  `json:"name"`
-		ShortDescription string  `json:"short_description"`
-		FullDescription  string  `json:"full_description"`
-		Price            float64 `json:"price"`
-		StockQuantity    int     `json:"stock_quantity"`
-		Images           []Image `json:"images"`
-		SKU              string  `json:"sku"`
-	}
-
-	type ApiResponse struct {
-		Products []Product `json:"products"`
-	}
-
-	var apiResponse ApiResponse
-	if err := json.Unmarshal(respBody, &apiResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if len(apiResponse.Products) == 0 {
-		return nil, fmt.Errorf("product not found")
-	}
-
-	product := apiResponse.Products[0]
+	itemDetails := product.ToItemDetails()
+	return &itemDetails, nil
+}
 
-	var imageURL string
-	if len(product.Images) > 0 {
-		imageURL = product.Images[0].Src
+func (r *ecommerceRepository) GetProduct(baseUrl, apiKey, itemId string) (*domain.Product, error) {
+	productID, err := ecommerceProductID(itemId)
+	if err != nil {
+		return nil, err
 	}
 
-	itemDetails := &domain.ItemDetails{
-		Item: domain.Item{
-			ItemId:      fmt.Sprintf("kivio-ecommerce∼%d", product.ID),
-			Name:        product.Name,
-			Description: product.ShortDescription,
-			ExternalId:  product.SKU,
-			Source:      "kivio ecommerce",
-			Url:         imageURL,
-		},
-		Availability: product.StockQuantity,
-		Price:        product.Price,
+	respBody, err := r.client.GetItemByID(baseUrl, apiKey, productID)
+	if err != nil {
+		return nil, err
 	}
 
-	return itemDetails, nil
+	return decodeProduct(respBody)
 }
 
 func (r *ecommerceRepository) GetItemByIDRaw(baseUrl, apiKey, itemId string) ([]byte, error) {
-	itemId = strings.TrimPrefix(itemId, "kivio-ecommerce∼")
-	return r.client.GetItemByID(baseUrl, apiKey, itemId)
+	productID, err := ecommerceProductID(itemId)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.GetItemByID(baseUrl, apiKey, productID)
 }
 
 func (r *ecommerceRepository) GetCustomers(baseUrl, apiKey string) ([]domain.Customer, error) {
@@ -347,7 +249,8 @@ func (r *ecommerceRepository) GetCustomers(baseUrl, apiKey string) ([]domain.Cus
 func (r *ecommerceRepository) GetAllCustomers(baseUrl, apiKey string) ([]domain.Customer, error) {
 	fmt.Printf("[GET_ALL_CUSTOMERS_REPO] Iniciando obtención de todos los clientes (con paginación)\n")
 
-	respBody, err := r.client.GetAllCustomers(baseUrl, apiKey)
+	roleID := r.registeredRoleID(baseUrl, apiKey)
+	respBody, err := r.client.GetAllCustomers(baseUrl, apiKey, roleID)
 	if err != nil {
 		fmt.Printf("[GET_ALL_CUSTOMERS_REPO] ERROR al obtener todos los clientes: %v\n", err)
 		return nil, err
@@ -375,8 +278,8 @@ func (r *ecommerceRepository) GetCustomerByID(baseUrl, apiKey, id string) (*doma
 	}
 
 	var customer domain.Customer
-	if err := json.Unmarshal(respBody, &customer); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if err := decodeEntity(respBody, "customer", "customers", &customer); err != nil {
+		return nil, err
 	}
 
 	return &customer, nil
@@ -430,35 +333,11 @@ func (r *ecommerceRepository) GetOrderEmails(baseUrl, apiKey string) ([]string,
 }
 
 func (r *ecommerceRepository) UpdateItemStock(baseUrl, apiKey, itemId string, newStock int64) error {
-	url := fmt.Sprintf("%s/api/products/%s", baseUrl, itemId)
-	payload := map[string]interface{}{
-		"product": map[string]interface{}{
-			"stock_quantity": newStock,
-		},
-	}
-	jsonPayload, err := json.Marshal(payload)
+	productID, err := ecommerceProductID(itemId)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	req, err := http.NewRequest("PUT", url, strings.NewReader(string(jsonPayload)))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 && resp.StatusCode != 204 {
-		return fmt.Errorf("failed to update stock, status code: %d", resp.StatusCode)
+		return err
 	}
-	return nil
+	return r.client.UpdateItemStock(baseUrl, apiKey, productID, newStock)
 }
 
 func (r *ecommerceRepository) GetAllItemsRaw(baseUrl, apiKey string) ([]byte, error) {
@@ -508,3 +387,24 @@ func (r *ecommerceRepository) UpdateOrder(baseUrl, apiKey string, orderID int, o
 func (r *ecommerceRepository) GetOrderByID(baseUrl, apiKey string, orderID int) ([]byte, error) {
 	return r.client.GetOrderByID(baseUrl, apiKey, orderID)
 }
+
+// ecommerceProductID accepts either a full item ref or a bare remote product ID
+// and returns the ID expected by the ecommerce API.
+func ecommerceProductID(itemId string) (string, error) {
+	if !strings.Contains(itemId, domain.ItemRefSeparator) {
+		ref, err := domain.NewItemRef(domain.KivioEcommerceProvider, itemId)
+		if err != nil {
+			return "", err
+		}
+		return ref.RemoteID, nil
+	}
+
+	ref, err := domain.ParseItemRef(itemId)
+	if err != nil {
+		return "", err
+	}
+	if ref.Provider != domain.KivioEcommerceProvider {
+		return "", fmt.Errorf("%w: unsupported provider %q", domain.ErrInvalidItemRef, ref.Provider)
+	}
+	return ref.RemoteID, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+const geoCacheTTL = time.Hour
+
+// geoCache keeps countries and states per store URL. They rarely change, and
+// every address needs them resolved. Callers always get a copy of the cached
+// slices, so they cannot modify the cache.
+type geoCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	countries map[string]geoCacheEntry
+	states    map[string]geoCacheEntry
+}
+
+type geoCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newGeoCache(ttl time.Duration) *geoCache {
+	return &geoCache{
+		ttl:       ttl,
+		countries: make(map[string]geoCacheEntry),
+		states:    make(map[string]geoCacheEntry),
+	}
+}
+
+func (c *geoCache) get(entries map[string]geoCacheEntry, key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *geoCache) put(entries map[string]geoCacheEntry, key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries[key] = geoCacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+func (r *ecommerceRepository) ListCountries(baseUrl, apiKey string) ([]domain.Country, error) {
+	if cached, ok := r.geo.get(r.geo.countries, baseUrl); ok {
+		return append([]domain.Country(nil), cached.([]domain.Country)...), nil
+	}
+
+	respBody, err := r.client.GetCountries(baseUrl, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Countries []domain.Country `json:"countries"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding countries response: %w", err)
+	}
+
+	r.geo.put(r.geo.countries, baseUrl, append([]domain.Country(nil), resp.Countries...))
+	return resp.Countries, nil
+}
+
+func (r *ecommerceRepository) ListStates(baseUrl, apiKey string, countryID int) ([]domain.StateProvince, error) {
+	key := fmt.Sprintf("%s#%d", baseUrl, countryID)
+	if cached, ok := r.geo.get(r.geo.states, key); ok {
+		return append([]domain.StateProvince(nil), cached.([]domain.StateProvince)...), nil
+	}
+
+	respBody, err := r.client.GetStates(baseUrl, apiKey, countryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		States []domain.StateProvince `json:"states"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding states response: %w", err)
+	}
+
+	r.geo.put(r.geo.states, key, append([]domain.StateProvince(nil), resp.States...))
+	return resp.States, nil
+}
+
+// ResolveAddressRegion fills CountryID and StateProvinceID from the Country and
+// StateProvince text of the address, e.g. "MX" and "Jalisco". IDs that are
+// already set are kept.
+func (r *ecommerceRepository) ResolveAddressRegion(baseUrl, apiKey string, address domain.Address) (domain.Address, error) {
+	if address.CountryID == 0 && address.Country != "" {
+		countries, err := r.ListCountries(baseUrl, apiKey)
+		if err != nil {
+			return address, fmt.Errorf("failed to list countries: %w", err)
+		}
+		country, ok := domain.FindCountry(countries, address.Country)
+		if !ok {
+			return address, fmt.Errorf("%w: %s", domain.ErrCountryNotFound, address.Country)
+		}
+		address.CountryID = country.ID
+		address.Country = country.Name
+	}
+
+	if address.StateProvinceID == 0 && address.StateProvince != "" && address.CountryID != 0 {
+		states, err := r.ListStates(baseUrl, apiKey, address.CountryID)
+		if err != nil {
+			return address, fmt.Errorf("failed to list states: %w", err)
+		}
+		state, ok := domain.FindState(states, address.StateProvince)
+		if !ok {
+			return address, fmt.Errorf("%w: %s", domain.ErrStateNotFound, address.StateProvince)
+		}
+		address.StateProvinceID = state.ID
+		address.StateProvince = state.Name
+	}
+
+	return address, nil
+}
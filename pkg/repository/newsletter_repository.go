@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+const newsletterPageLimit = 250
+
+func (r *ecommerceRepository) ListNewsletterSubscriptions(baseUrl, apiKey string) ([]domain.NewsletterSubscription, error) {
+	var subscriptions []domain.NewsletterSubscription
+	for page := 1; ; page++ {
+		respBody, err := r.client.GetNewsletterSubscriptions(baseUrl, apiKey, page, newsletterPageLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			Subscriptions []domain.NewsletterSubscription `json:"news_letter_subscriptions"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return nil, fmt.Errorf("error decoding newsletter subscriptions response: %w", err)
+		}
+
+		subscriptions = append(subscriptions, resp.Subscriptions...)
+		if len(resp.Subscriptions) < newsletterPageLimit {
+			return subscriptions, nil
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+// CreateOrderIdempotent tags the order payload with the idempotency key and
+// only posts it when no order with that key exists yet. When the POST fails
+// (for example on a timeout after the server committed) the lookup is repeated,
+// so the original order is returned instead of an error or a duplicate.
+func (r *ecommerceRepository) CreateOrderIdempotent(baseUrl, apiKey string, orderData []byte, idempotencyKey string) ([]byte, error) {
+	tagged, customerID, err := tagOrderPayload(orderData, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := r.findOrderByIdempotencyKey(baseUrl, apiKey, customerID, idempotencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up orders with idempotency key %s: %w", idempotencyKey, err)
+	}
+	if existing != nil {
+		fmt.Printf("[ORDER_IDEMPOTENCY] Orden existente encontrada para la clave %s\n", idempotencyKey)
+		return ordersEnvelope(existing)
+	}
+
+	respBody, createErr := r.client.CreateOrder(baseUrl, apiKey, tagged)
+	if createErr == nil {
+		return respBody, nil
+	}
+
+	existing, err = r.findOrderByIdempotencyKey(baseUrl, apiKey, customerID, idempotencyKey)
+	if err != nil || existing == nil {
+		return nil, createErr
+	}
+
+	fmt.Printf("[ORDER_IDEMPOTENCY] La creación falló (%v) pero la orden con clave %s ya existe\n", createErr, idempotencyKey)
+	return ordersEnvelope(existing)
+}
+
+func (r *ecommerceRepository) FindOrderByIdempotencyKey(baseUrl, apiKey string, customerID int, idempotencyKey string) (*domain.Order, error) {
+	raw, err := r.findOrderByIdempotencyKey(baseUrl, apiKey, customerID, idempotencyKey)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+
+	var order domain.Order
+	if err := json.Unmarshal(raw, &order); err != nil {
+		return nil, fmt.Errorf("error decoding order: %w", err)
+	}
+	return &order, nil
+}
+
+// findOrderByIdempotencyKey scans the customer's orders for one tagged with the
+// key. It returns nil when no order matches.
+func (r *ecommerceRepository) findOrderByIdempotencyKey(baseUrl, apiKey string, customerID int, idempotencyKey string) (json.RawMessage, error) {
+	if customerID <= 0 {
+		return nil, fmt.Errorf("a customer ID is required to look up orders by idempotency key")
+	}
+
+	respBody, err := r.client.GetCustomerOrders(baseUrl, apiKey, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Orders []json.RawMessage `json:"orders"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding orders response: %w", err)
+	}
+
+	for _, raw := range resp.Orders {
+		var order struct {
+			CustomValues map[string]interface{} `json:"custom_values"`
+		}
+		if err := json.Unmarshal(raw, &order); err != nil {
+			continue
+		}
+		if value, ok := order.CustomValues[domain.OrderIdempotencyKeyAttribute]; ok && fmt.Sprint(value) == idempotencyKey {
+			return raw, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// tagOrderPayload adds the idempotency key to the custom values of a raw
+// {"order": {...}} payload and returns the customer ID it references. Only the
+// order object is rewritten; other top-level keys are passed through as is.
+func tagOrderPayload(orderData []byte, idempotencyKey string) ([]byte, int, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(orderData, &payload); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode order payload: %w", err)
+	}
+
+	rawOrder, ok := payload["order"]
+	if !ok {
+		return nil, 0, fmt.Errorf("order payload is missing the \"order\" object")
+	}
+
+	var order map[string]json.RawMessage
+	if err := json.Unmarshal(rawOrder, &order); err != nil || order == nil {
+		return nil, 0, fmt.Errorf("order payload has an invalid \"order\" object")
+	}
+
+	var customerID int
+	if raw, ok := order["customer_id"]; ok {
+		if err := json.Unmarshal(raw, &customerID); err != nil {
+			return nil, 0, fmt.Errorf("order payload has an invalid customer_id: %w", err)
+		}
+	}
+	if customerID <= 0 {
+		return nil, 0, fmt.Errorf("order payload must reference a customer_id to be created idempotently")
+	}
+
+	customValues := make(map[string]json.RawMessage)
+	if raw, ok := order["custom_values"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &customValues); err != nil {
+			return nil, 0, fmt.Errorf("order payload has invalid custom_values: %w", err)
+		}
+	}
+
+	var err error
+	if customValues[domain.OrderIdempotencyKeyAttribute], err = json.Marshal(idempotencyKey); err != nil {
+		return nil, 0, err
+	}
+	if order["custom_values"], err = json.Marshal(customValues); err != nil {
+		return nil, 0, err
+	}
+	if payload["order"], err = json.Marshal(order); err != nil {
+		return nil, 0, err
+	}
+
+	tagged, err := json.Marshal(payload)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal order payload: %w", err)
+	}
+	return tagged, customerID, nil
+}
+
+func ordersEnvelope(order json.RawMessage) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"orders": []json.RawMessage{order},
+	})
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/client"
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+const defaultOrderPageLimit = 100
+
+// ListOrders returns the orders matching the query. When query.Page is zero
+// every page is fetched. Filters are re-applied locally because not every
+// store honours all of them.
+func (r *ecommerceRepository) ListOrders(baseUrl, apiKey string, query domain.OrderQuery) ([]domain.Order, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultOrderPageLimit
+	}
+
+	page := query.Page
+	allPages := page <= 0
+	if allPages {
+		page = 1
+	}
+
+	var orders []domain.Order
+	for {
+		params := orderQueryParams(query)
+		params.Set("Page", strconv.Itoa(page))
+		params.Set("Limit", strconv.Itoa(limit))
+
+		respBody, err := r.client.ListOrders(baseUrl, apiKey, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			Orders []domain.Order `json:"orders"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return nil, fmt.Errorf("error decoding orders response: %w", err)
+		}
+
+		for i := range resp.Orders {
+			if query.Matches(&resp.Orders[i]) {
+				orders = append(orders, resp.Orders[i])
+			}
+		}
+
+		if !allPages || len(resp.Orders) < limit {
+			break
+		}
+
+		page++
+	}
+
+	return orders, nil
+}
+
+// UpdateOrderFields sends a partial order update with only the given fields.
+func (r *ecommerceRepository) UpdateOrderFields(baseUrl, apiKey string, orderID int, fields map[string]interface{}) error {
+	payload, err := encodeEntity("order", fields)
+	if err != nil {
+		return err
+	}
+	return r.client.UpdateOrder(baseUrl, apiKey, orderID, payload)
+}
+
+func (r *ecommerceRepository) RefundOrder(baseUrl, apiKey string, orderID int, refund domain.RefundRequest) error {
+	payload, err := encodeEntity("refund", refund)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.RefundOrder(baseUrl, apiKey, orderID, payload)
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.IsNotSupported() {
+		return fmt.Errorf("%w: %v", domain.ErrRefundNotSupported, err)
+	}
+	return err
+}
+
+func (r *ecommerceRepository) AddOrderNote(baseUrl, apiKey string, note domain.OrderNote) (*domain.OrderNote, error) {
+	payload, err := encodeEntity("order_note", note)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.client.CreateOrderNote(baseUrl, apiKey, note.OrderID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var created domain.OrderNote
+	if err := decodeEntity(respBody, "order_note", "order_notes", &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *ecommerceRepository) ListOrderNotes(baseUrl, apiKey string, orderID int) ([]domain.OrderNote, error) {
+	respBody, err := r.client.GetOrderNotes(baseUrl, apiKey, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		OrderNotes []domain.OrderNote `json:"order_notes"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding order notes response: %w", err)
+	}
+	return resp.OrderNotes, nil
+}
+
+func orderQueryParams(query domain.OrderQuery) url.Values {
+	params := url.Values{}
+	if query.CustomerID != 0 {
+		params.Set("CustomerId", strconv.Itoa(query.CustomerID))
+	}
+	if query.OrderStatus != "" {
+		params.Set("OrderStatus", string(query.OrderStatus))
+	}
+	if query.PaymentStatus != "" {
+		params.Set("PaymentStatus", string(query.PaymentStatus))
+	}
+	if query.ShippingStatus != "" {
+		params.Set("ShippingStatus", string(query.ShippingStatus))
+	}
+	if query.CreatedAtMin != nil {
+		params.Set("CreatedAtMin", query.CreatedAtMin.UTC().Format(time.RFC3339))
+	}
+	if query.CreatedAtMax != nil {
+		params.Set("CreatedAtMax", query.CreatedAtMax.UTC().Format(time.RFC3339))
+	}
+	if query.StoreID != 0 {
+		params.Set("StoreId", strconv.Itoa(query.StoreID))
+	}
+	return params
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+// productsResponse is the envelope of every product endpoint. All item
+// methods decode products through it and map them with domain.Product, so
+// they fill in the same fields.
+type productsResponse struct {
+	Products []domain.Product `json:"products"`
+	Total    int              `json:"total"`
+	Pages    int              `json:"pages"`
+}
+
+func decodeProducts(body []byte) ([]domain.Product, error) {
+	var resp productsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal products: %w", err)
+	}
+	return resp.Products, nil
+}
+
+func decodeProduct(body []byte) (*domain.Product, error) {
+	var product domain.Product
+	if err := decodeEntity(body, "product", "products", &product); err != nil {
+		return nil, err
+	}
+	if product.ID == 0 {
+		return nil, fmt.Errorf("product not found")
+	}
+	return &product, nil
+}
+
+// isSellable reports whether a product can be offered: published and in stock.
+func isSellable(product *domain.Product) bool {
+	return product.Published && product.StockQuantity > 0
+}
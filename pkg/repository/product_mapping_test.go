@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/client"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// productClient serves a fixed products response to the product endpoints.
+type productClient struct {
+	client.EcommerceClient
+	body []byte
+}
+
+func (c *productClient) GetItems(baseUrl, apiKey string, page, limit int, publishedStatus bool, filters map[string]string) ([]byte, error) {
+	if page > 1 {
+		return []byte(`{"products":[]}`), nil
+	}
+	return c.body, nil
+}
+
+// GetItemByID serves the fixture bytes of the matching product as they are,
+// so the test exercises decoding of the raw API response.
+func (c *productClient) GetItemByID(baseUrl, apiKey, itemId string) ([]byte, error) {
+	var resp struct {
+		Products []json.RawMessage `json:"products"`
+	}
+	if err := json.Unmarshal(c.body, &resp); err != nil {
+		return nil, err
+	}
+	for _, raw := range resp.Products {
+		var product struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &product); err != nil {
+			return nil, err
+		}
+		if itemId == strconv.Itoa(product.ID) {
+			return append(append([]byte(`{"products":[`), raw...), ']', '}'), nil
+		}
+	}
+	return []byte(`{"products":[]}`), nil
+}
+
+func newProductTestRepository(t *testing.T) *ecommerceRepository {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "products.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ecommerceRepository{client: &productClient{body: body}}
+}
+
+func TestProductMappingGolden(t *testing.T) {
+	repo := newProductTestRepository(t)
+
+	items, err := repo.GetItems("", "", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "get_items.golden.json", items)
+
+	cursorItems, next, err := repo.GetItemsWithLastItem("", "", "", 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "get_items_with_last_item.golden.json", map[string]interface{}{
+		"items":        cursorItems,
+		"next_item_id": next,
+	})
+
+	item, err := repo.GetItemByID("", "", "101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "get_item_by_id.golden.json", item)
+
+	details, err := repo.GetItemByIDWithDetails("", "", "kivio-ecommerce∼101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "get_item_by_id_with_details.golden.json", details)
+
+	product, err := repo.GetProduct("", "", "101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "get_product.golden.json", product)
+}
+
+func TestItemMethodsMapSharedFieldsConsistently(t *testing.T) {
+	repo := newProductTestRepository(t)
+
+	items, err := repo.GetItems("", "", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, listed := range items {
+		item, err := repo.GetItemByID("", "", listed.ItemId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *item != listed {
+			t.Errorf("GetItemByID(%s) = %+v, GetItems returned %+v", listed.ItemId, *item, listed)
+		}
+
+		details, err := repo.GetItemByIDWithDetails("", "", listed.ItemId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if details.Item != listed {
+			t.Errorf("GetItemByIDWithDetails(%s).Item = %+v, GetItems returned %+v", listed.ItemId, details.Item, listed)
+		}
+	}
+}
+
+func TestGetItemByIDNotFound(t *testing.T) {
+	repo := newProductTestRepository(t)
+
+	if _, err := repo.GetItemByID("", "", "999"); err == nil {
+		t.Fatal("expected an error for a missing product")
+	}
+}
+
+func assertGolden(t *testing.T, name string, value interface{}) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+func (r *ecommerceRepository) CreateShipment(baseUrl, apiKey string, shipment domain.Shipment) (*domain.Shipment, error) {
+	payload, err := encodeEntity("shipment", shipment)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.client.CreateShipment(baseUrl, apiKey, shipment.OrderID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var created domain.Shipment
+	if err := decodeEntity(respBody, "shipment", "shipments", &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *ecommerceRepository) ListOrderShipments(baseUrl, apiKey string, orderID int) ([]domain.Shipment, error) {
+	respBody, err := r.client.GetOrderShipments(baseUrl, apiKey, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Shipments []domain.Shipment `json:"shipments"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding shipments response: %w", err)
+	}
+	return resp.Shipments, nil
+}
+
+func (r *ecommerceRepository) MarkShipmentDelivered(baseUrl, apiKey string, shipmentID int, deliveredAt time.Time) error {
+	payload, err := encodeEntity("shipment", map[string]interface{}{
+		"delivery_date_utc": deliveredAt.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	return r.client.UpdateShipment(baseUrl, apiKey, shipmentID, payload)
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+// StockLedgerStore keeps the audit trail of stock changes applied to the
+// ecommerce store. Entries are returned in the order they were appended.
+type StockLedgerStore interface {
+	AppendStockLedgerEntry(ctx context.Context, entry domain.StockLedgerEntry) error
+	ListStockLedgerEntries(ctx context.Context, itemRef domain.ItemRef) ([]domain.StockLedgerEntry, error)
+}
+
+type inMemoryStockLedgerStore struct {
+	mu      sync.Mutex
+	entries []domain.StockLedgerEntry
+}
+
+func NewInMemoryStockLedgerStore() StockLedgerStore {
+	return &inMemoryStockLedgerStore{}
+}
+
+func (s *inMemoryStockLedgerStore) AppendStockLedgerEntry(ctx context.Context, entry domain.StockLedgerEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *inMemoryStockLedgerStore) ListStockLedgerEntries(ctx context.Context, itemRef domain.ItemRef) ([]domain.StockLedgerEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []domain.StockLedgerEntry
+	for _, entry := range s.entries {
+		if entry.ItemRef == itemRef {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+// StockReservationStore persists auction stock reservations. GetReservation
+// returns nil without error when the reservation does not exist.
+type StockReservationStore interface {
+	GetReservation(ctx context.Context, reservationID string) (*domain.StockReservation, error)
+	SaveReservation(ctx context.Context, reservation *domain.StockReservation) error
+}
+
+type inMemoryStockReservationStore struct {
+	mu           sync.Mutex
+	reservations map[string]domain.StockReservation
+}
+
+func NewInMemoryStockReservationStore() StockReservationStore {
+	return &inMemoryStockReservationStore{
+		reservations: make(map[string]domain.StockReservation),
+	}
+}
+
+func (s *inMemoryStockReservationStore) GetReservation(ctx context.Context, reservationID string) (*domain.StockReservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservation, ok := s.reservations[reservationID]
+	if !ok {
+		return nil, nil
+	}
+	return &reservation, nil
+}
+
+func (s *inMemoryStockReservationStore) SaveReservation(ctx context.Context, reservation *domain.StockReservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reservations[reservation.ReservationID] = *reservation
+	return nil
+}
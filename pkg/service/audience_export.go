@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+const audienceCustomerPageLimit = 100
+
+type AudienceExportOptions struct {
+	Format domain.AudienceFormat
+	// OnlySubscribed drops members without an active newsletter subscription.
+	OnlySubscribed bool
+}
+
+// AudienceExport merges customers and order billing emails into a deduplicated
+// audience with consent, last order date and total spend, and streams it to w.
+// Customers are read page by page and written as each page arrives; members
+// that only appear on orders are written last. It returns the number of
+// members written.
+func (s *ecommerceService) AudienceExport(ctx context.Context, apiUrl, apiKey string, w io.Writer, opts AudienceExportOptions) (int, error) {
+	if opts.Format == "" {
+		opts.Format = domain.AudienceFormatCSV
+	}
+	writer, err := domain.NewAudienceWriter(w, opts.Format)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("[AUDIENCE] Iniciando exportación de audiencia (formato %s)\n", opts.Format)
+
+	orders, err := s.repo.ListOrders(apiUrl, apiKey, domain.OrderQuery{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	subscriptions, err := s.repo.ListNewsletterSubscriptions(apiUrl, apiKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list newsletter subscriptions: %w", err)
+	}
+
+	builder := domain.NewAudienceBuilder(orders, subscriptions)
+	written := 0
+	write := func(members []domain.AudienceMember) error {
+		for _, member := range members {
+			if opts.OnlySubscribed && member.Consent != domain.ConsentSubscribed {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := writer.Write(member); err != nil {
+				return fmt.Errorf("failed to write audience member: %w", err)
+			}
+			written++
+		}
+		return nil
+	}
+
+	for page := 1; ; page++ {
+		customers, err := s.repo.ListCustomers(apiUrl, apiKey, domain.CustomerQuery{Page: page, Limit: audienceCustomerPageLimit})
+		if err != nil {
+			return written, fmt.Errorf("failed to list customers page %d: %w", page, err)
+		}
+		if err := write(builder.AddCustomers(customers)); err != nil {
+			return written, err
+		}
+		if len(customers) < audienceCustomerPageLimit {
+			break
+		}
+	}
+
+	if err := write(builder.Remaining()); err != nil {
+		return written, err
+	}
+
+	fmt.Printf("[AUDIENCE] Exportación completada: %d contactos\n", written)
+	return written, nil
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/repository"
+)
+
+var ErrCheckoutFailed = errors.New("checkout failed")
+
+type CheckoutService interface {
+	Checkout(ctx context.Context, apiUrl, apiKey string, req domain.CheckoutRequest) (*domain.CheckoutResult, error)
+	GetCheckoutLog(ctx context.Context, checkoutID string) (*domain.CheckoutLog, error)
+}
+
+type checkoutService struct {
+	repo  repository.EcommerceRepository
+	logs  repository.CheckoutLogStore
+	locks *keyedMutex
+}
+
+func NewCheckoutService(repo repository.EcommerceRepository, logs repository.CheckoutLogStore) CheckoutService {
+	return &checkoutService{
+		repo:  repo,
+		logs:  logs,
+		locks: newKeyedMutex(),
+	}
+}
+
+// checkoutRun carries the state shared by the steps of a single checkout.
+type checkoutRun struct {
+	apiUrl    string
+	apiKey    string
+	req       domain.CheckoutRequest
+	productID int
+	log       *domain.CheckoutLog
+}
+
+// checkoutStep is one step of the saga. An optional step that fails does not
+// undo the checkout; it is left pending so that the next call retries it.
+type checkoutStep struct {
+	name       domain.CheckoutStep
+	run        func(ctx context.Context, run *checkoutRun) error
+	compensate func(ctx context.Context, run *checkoutRun) error
+	optional   bool
+}
+
+func (s *checkoutService) steps() []checkoutStep {
+	return []checkoutStep{
+		{name: domain.CheckoutStepValidateCart, run: s.validateCart},
+		{name: domain.CheckoutStepCustomer, run: s.createCustomer, compensate: s.deleteCustomer},
+		{name: domain.CheckoutStepBillingAddress, run: s.createBillingAddress, compensate: s.deleteBillingAddress},
+		{name: domain.CheckoutStepShippingAddress, run: s.createShippingAddress, compensate: s.deleteShippingAddress},
+		{name: domain.CheckoutStepClearCart, run: s.clearCart},
+		{name: domain.CheckoutStepCartItem, run: s.addCartItem, compensate: s.clearCart},
+		{name: domain.CheckoutStepOrder, run: s.placeOrder, compensate: s.deleteOrder},
+		{name: domain.CheckoutStepItemPrice, run: s.setItemPrice},
+		{name: domain.CheckoutStepOrderNote, run: s.addAuctionNote, optional: true},
+	}
+}
+
+// Checkout runs the winner checkout as a saga. Every completed step is saved in
+// the checkout log, so calling Checkout again with the same request resumes an
+// interrupted checkout; when a step fails the completed steps are compensated
+// in reverse order.
+func (s *checkoutService) Checkout(ctx context.Context, apiUrl, apiKey string, req domain.CheckoutRequest) (*domain.CheckoutResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	productID, _ := req.Lot.ItemRef.NumericID()
+	checkoutID := req.ID()
+
+	unlock := s.locks.Lock(checkoutID)
+	defer unlock()
+
+	log, err := s.logs.GetCheckoutLog(ctx, checkoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkout log %s: %w", checkoutID, err)
+	}
+	if log == nil || log.Status == domain.CheckoutCompensated {
+		log = &domain.CheckoutLog{CheckoutID: checkoutID, Status: domain.CheckoutInProgress}
+	}
+	if log.Status == domain.CheckoutCompensationFailed {
+		return nil, fmt.Errorf("%w: checkout %s needs manual cleanup: %s", ErrCheckoutFailed, checkoutID, log.LastError)
+	}
+
+	run := &checkoutRun{apiUrl: apiUrl, apiKey: apiKey, req: req, productID: productID, log: log}
+
+	steps := s.steps()
+	if log.Status != domain.CheckoutCompleted || hasPendingSteps(log, steps) {
+		fmt.Printf("[CHECKOUT] Iniciando checkout %s (pasos completados: %d)\n", checkoutID, len(log.CompletedSteps))
+
+		for i, step := range steps {
+			if log.HasCompleted(step.name) {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			fmt.Printf("[CHECKOUT] %s: ejecutando paso %s\n", checkoutID, step.name)
+			if err := step.run(ctx, run); err != nil {
+				if step.optional {
+					fmt.Printf("[CHECKOUT] %s: el paso opcional %s falló y se reintentará: %v\n", checkoutID, step.name, err)
+					log.LastError = fmt.Sprintf("step %s: %v", step.name, err)
+					continue
+				}
+				stepErr := fmt.Errorf("%w: step %s: %v", ErrCheckoutFailed, step.name, err)
+				fmt.Printf("[CHECKOUT] ERROR en %s: %v\n", checkoutID, stepErr)
+				return nil, s.compensate(ctx, run, steps[:i], stepErr)
+			}
+
+			log.MarkCompleted(step.name)
+			if err := s.saveLog(ctx, log); err != nil {
+				return nil, err
+			}
+		}
+
+		log.Status = domain.CheckoutCompleted
+		if !hasPendingSteps(log, steps) {
+			log.LastError = ""
+		}
+		if err := s.saveLog(ctx, log); err != nil {
+			return nil, err
+		}
+		fmt.Printf("[CHECKOUT] Checkout %s completado: orden %d por %.2f\n", checkoutID, log.OrderID, log.FinalPrice)
+	}
+
+	order, err := s.repo.GetOrder(apiUrl, apiKey, log.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("checkout %s completed but failed to read order %d: %w", checkoutID, log.OrderID, err)
+	}
+
+	return &domain.CheckoutResult{
+		CheckoutID: checkoutID,
+		CustomerID: log.CustomerID,
+		Order:      order,
+		FinalPrice: log.FinalPrice,
+	}, nil
+}
+
+func hasPendingSteps(log *domain.CheckoutLog, steps []checkoutStep) bool {
+	for _, step := range steps {
+		if !log.HasCompleted(step.name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *checkoutService) GetCheckoutLog(ctx context.Context, checkoutID string) (*domain.CheckoutLog, error) {
+	return s.logs.GetCheckoutLog(ctx, checkoutID)
+}
+
+func (s *checkoutService) compensate(ctx context.Context, run *checkoutRun, completed []checkoutStep, cause error) error {
+	log := run.log
+	log.LastError = cause.Error()
+
+	var compensationErrs []error
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.compensate == nil || !log.HasCompleted(step.name) {
+			continue
+		}
+
+		fmt.Printf("[CHECKOUT] %s: compensando paso %s\n", log.CheckoutID, step.name)
+		if err := step.compensate(ctx, run); err != nil {
+			compensationErrs = append(compensationErrs, fmt.Errorf("compensate %s: %w", step.name, err))
+		}
+	}
+
+	if len(compensationErrs) > 0 {
+		log.Status = domain.CheckoutCompensationFailed
+		log.LastError = errors.Join(append([]error{cause}, compensationErrs...)...).Error()
+	} else {
+		log.Status = domain.CheckoutCompensated
+		log.CompletedSteps = nil
+	}
+
+	if err := s.saveLog(ctx, log); err != nil {
+		return errors.Join(cause, err)
+	}
+	if len(compensationErrs) > 0 {
+		return errors.Join(append([]error{cause}, compensationErrs...)...)
+	}
+	return cause
+}
+
+func (s *checkoutService) saveLog(ctx context.Context, log *domain.CheckoutLog) error {
+	log.UpdatedAt = time.Now().UTC()
+	if err := s.logs.SaveCheckoutLog(ctx, log); err != nil {
+		return fmt.Errorf("failed to save checkout log %s: %w", log.CheckoutID, err)
+	}
+	return nil
+}
+
+func (s *checkoutService) createCustomer(ctx context.Context, run *checkoutRun) error {
+	winner := run.req.Winner
+	customer, created, err := ensureCustomer(ctx, s.repo, run.apiUrl, run.apiKey, domain.CustomerInput{
+		Email:     winner.Email,
+		FirstName: winner.FirstName,
+		LastName:  winner.LastName,
+		Phone:     winner.Phone,
+	})
+	if err != nil {
+		return err
+	}
+
+	run.log.CustomerID = customer.ID
+	run.log.CustomerCreated = created
+	return nil
+}
+
+func (s *checkoutService) deleteCustomer(ctx context.Context, run *checkoutRun) error {
+	if !run.log.CustomerCreated || run.log.CustomerID == 0 {
+		return nil
+	}
+	return s.repo.DeleteCustomer(run.apiUrl, run.apiKey, run.log.CustomerID)
+}
+
+func (s *checkoutService) createBillingAddress(ctx context.Context, run *checkoutRun) error {
+	address, created, err := ensureAddress(ctx, s.repo, run.apiUrl, run.apiKey, run.log.CustomerID, run.billingAddress(), domain.AddressKindBilling)
+	if err != nil {
+		return err
+	}
+
+	run.log.BillingAddressID = address.ID
+	run.log.BillingAddressCreated = created
+	return nil
+}
+
+func (s *checkoutService) createShippingAddress(ctx context.Context, run *checkoutRun) error {
+	address, created, err := ensureAddress(ctx, s.repo, run.apiUrl, run.apiKey, run.log.CustomerID, run.shippingAddress(), domain.AddressKindShipping)
+	if err != nil {
+		return err
+	}
+
+	run.log.ShippingAddressID = address.ID
+	run.log.ShippingAddressCreated = created
+	return nil
+}
+
+// deleteBillingAddress and deleteShippingAddress only remove addresses this
+// checkout created for a returning customer; new customers are deleted whole.
+func (s *checkoutService) deleteBillingAddress(ctx context.Context, run *checkoutRun) error {
+	if !run.log.BillingAddressCreated || run.log.CustomerCreated || run.log.BillingAddressID == 0 {
+		return nil
+	}
+	return s.repo.DeleteCustomerAddress(run.apiUrl, run.apiKey, run.log.CustomerID, run.log.BillingAddressID)
+}
+
+func (s *checkoutService) deleteShippingAddress(ctx context.Context, run *checkoutRun) error {
+	if !run.log.ShippingAddressCreated || run.log.CustomerCreated || run.log.ShippingAddressID == 0 ||
+		run.log.ShippingAddressID == run.log.BillingAddressID {
+		return nil
+	}
+	return s.repo.DeleteCustomerAddress(run.apiUrl, run.apiKey, run.log.CustomerID, run.log.ShippingAddressID)
+}
+
+func (s *checkoutService) validateCart(ctx context.Context, run *checkoutRun) error {
+	reserved := 0
+	if run.req.ReservationID != "" {
+		reserved = run.req.Lot.Quantity
+	}
+	return validateCartItem(s.repo, run.apiUrl, run.apiKey, run.productID, run.req.Lot.Quantity, reserved)
+}
+
+func (s *checkoutService) clearCart(ctx context.Context, run *checkoutRun) error {
+	return s.repo.DeleteShoppingCart(run.apiUrl, run.apiKey, run.log.CustomerID)
+}
+
+func (s *checkoutService) addCartItem(ctx context.Context, run *checkoutRun) error {
+	item, err := s.repo.AddShoppingCartItem(run.apiUrl, run.apiKey, domain.ShoppingCartItem{
+		CustomerID:       run.log.CustomerID,
+		ProductID:        run.productID,
+		Quantity:         run.req.Lot.Quantity,
+		ShoppingCartType: domain.ShoppingCartTypeCart,
+		StoreID:          run.req.StoreID,
+	})
+	if err != nil {
+		return err
+	}
+
+	run.log.CartItemID = item.ID
+	return nil
+}
+
+func (s *checkoutService) placeOrder(ctx context.Context, run *checkoutRun) error {
+	billing := run.billingAddress()
+	shipping := run.shippingAddress()
+
+	order := domain.Order{
+		CustomerID:              run.log.CustomerID,
+		StoreID:                 run.req.StoreID,
+		PaymentMethodSystemName: run.req.PaymentMethodSystemName,
+		ShippingMethod:          run.req.ShippingMethod,
+		BillingAddress:          &billing,
+		ShippingAddress:         &shipping,
+	}
+	order.SetCustomValue(domain.OrderIdempotencyKeyAttribute, run.log.CheckoutID)
+
+	created, err := s.repo.PlaceOrder(run.apiUrl, run.apiKey, order)
+	if err != nil {
+		return err
+	}
+
+	item, ok := created.FindItemByProductID(run.productID)
+	if !ok {
+		err := fmt.Errorf("order %d does not contain product %d", created.ID, run.productID)
+		if deleteErr := s.repo.DeleteOrder(run.apiUrl, run.apiKey, created.ID); deleteErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to delete order %d: %w", created.ID, deleteErr))
+		}
+		return err
+	}
+
+	run.log.OrderID = created.ID
+	run.log.OrderItemID = item.ID
+	return nil
+}
+
+func (s *checkoutService) deleteOrder(ctx context.Context, run *checkoutRun) error {
+	if run.log.OrderID == 0 {
+		return nil
+	}
+	return s.repo.DeleteOrder(run.apiUrl, run.apiKey, run.log.OrderID)
+}
+
+func (s *checkoutService) setItemPrice(ctx context.Context, run *checkoutRun) error {
+	order, err := setOrderItemLineTotal(s.repo, run.apiUrl, run.apiKey, run.log.OrderID, run.log.OrderItemID, run.req.WinningBid, domain.OrderItemPriceOptions{})
+	if err != nil {
+		return err
+	}
+
+	run.log.FinalPrice = run.req.WinningBid
+	if item, ok := order.FindItemByProductID(run.productID); ok {
+		run.log.FinalPrice = item.PriceInclTax
+	}
+	return nil
+}
+
+// addAuctionNote links the order to the auction. It is an optional step: a
+// failure leaves it pending for the next Checkout call instead of undoing an
+// otherwise successful checkout.
+func (s *checkoutService) addAuctionNote(ctx context.Context, run *checkoutRun) error {
+	note := domain.AuctionNote{
+		AuctionID:     run.req.Lot.AuctionID,
+		LotNumber:     run.req.Lot.LotNumber,
+		WinningBid:    run.req.WinningBid,
+		BidHistoryURL: run.req.Lot.BidHistoryURL,
+	}
+
+	_, err := s.repo.AddOrderNote(run.apiUrl, run.apiKey, domain.OrderNote{
+		OrderID: run.log.OrderID,
+		Note:    note.String(),
+	})
+	return err
+}
+
+func (run *checkoutRun) billingAddress() domain.Address {
+	address := run.req.Winner.BillingAddress
+	if address.Email == "" {
+		address.Email = run.req.Winner.Email
+	}
+	if address.FirstName == "" {
+		address.FirstName = run.req.Winner.FirstName
+	}
+	if address.LastName == "" {
+		address.LastName = run.req.Winner.LastName
+	}
+	if address.PhoneNumber == "" {
+		address.PhoneNumber = run.req.Winner.Phone
+	}
+	address.ID = run.log.BillingAddressID
+	return address
+}
+
+func (run *checkoutRun) shippingAddress() domain.Address {
+	if run.req.Winner.ShippingAddress == nil {
+		address := run.billingAddress()
+		address.ID = run.log.ShippingAddressID
+		return address
+	}
+
+	address := *run.req.Winner.ShippingAddress
+	if address.Email == "" {
+		address.Email = run.req.Winner.Email
+	}
+	address.ID = run.log.ShippingAddressID
+	return address
+}
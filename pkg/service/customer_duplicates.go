@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+const (
+	defaultDuplicateMinScore = 0.5
+	defaultReassignMinScore  = 1.0
+)
+
+type DuplicateCustomerOptions struct {
+	// MinScore is the minimum score for a pair to be reported. Defaults to 0.5.
+	MinScore float64
+	// Reassign moves the orders of each duplicate to its primary customer.
+	// Without it the job is a dry run and only lists the planned moves.
+	Reassign bool
+	// ReassignMinScore limits reassignment to confident candidates. Defaults
+	// to 1, which requires at least an exact email match.
+	ReassignMinScore float64
+}
+
+// FindDuplicateCustomers scans every customer for likely duplicates and
+// reports them ranked by score, with their order counts.
+func (s *ecommerceService) FindDuplicateCustomers(ctx context.Context, apiUrl, apiKey string, opts DuplicateCustomerOptions) (*domain.DuplicateReport, error) {
+	if opts.MinScore <= 0 {
+		opts.MinScore = defaultDuplicateMinScore
+	}
+	if opts.ReassignMinScore <= 0 {
+		opts.ReassignMinScore = defaultReassignMinScore
+	}
+
+	customers, err := s.repo.ListCustomers(apiUrl, apiKey, domain.CustomerQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customers: %w", err)
+	}
+
+	orders, err := s.repo.ListOrders(apiUrl, apiKey, domain.OrderQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	orderCounts := make(map[int]int)
+	ordersByCustomer := make(map[int][]int)
+	for _, order := range orders {
+		orderCounts[order.CustomerID]++
+		ordersByCustomer[order.CustomerID] = append(ordersByCustomer[order.CustomerID], order.ID)
+	}
+
+	report := &domain.DuplicateReport{
+		CustomersScanned: len(customers),
+		Candidates:       domain.FindDuplicateCustomers(customers, orderCounts, opts.MinScore),
+		DryRun:           !opts.Reassign,
+	}
+
+	fmt.Printf("[DUPLICATES] %d clientes analizados, %d posibles duplicados\n", len(customers), len(report.Candidates))
+
+	for _, candidate := range report.Candidates {
+		if candidate.Score < opts.ReassignMinScore {
+			continue
+		}
+
+		for _, orderID := range ordersByCustomer[candidate.DuplicateCustomerID] {
+			reassignment := domain.OrderReassignment{
+				OrderID:        orderID,
+				FromCustomerID: candidate.DuplicateCustomerID,
+				ToCustomerID:   candidate.PrimaryCustomerID,
+			}
+
+			if opts.Reassign {
+				if err := ctx.Err(); err != nil {
+					return report, err
+				}
+				err := s.repo.UpdateOrderFields(apiUrl, apiKey, orderID, map[string]interface{}{
+					"customer_id": candidate.PrimaryCustomerID,
+				})
+				if err != nil {
+					fmt.Printf("[DUPLICATES] ERROR al reasignar la orden %d: %v\n", orderID, err)
+					reassignment.Error = err.Error()
+				}
+			}
+
+			report.Reassignments = append(report.Reassignments, reassignment)
+		}
+	}
+
+	return report, nil
+}
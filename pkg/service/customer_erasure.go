@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+// EraseCustomer handles a data-protection erasure request. The customer's
+// addresses and account are anonymized or deleted through the API; orders are
+// kept, and the report lists those that still hold personal data.
+func (s *ecommerceService) EraseCustomer(ctx context.Context, apiUrl, apiKey string, customerID int, mode domain.ErasureMode) (*domain.ErasureReport, error) {
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := &domain.ErasureReport{CustomerID: customerID, Mode: mode}
+
+	orders, err := s.repo.ListOrders(apiUrl, apiKey, domain.OrderQuery{CustomerID: customerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders of customer %d: %w", customerID, err)
+	}
+
+	fmt.Printf("[ERASURE] Borrando datos del cliente %d (modo %s)\n", customerID, mode)
+
+	addresses, err := s.repo.ListCustomerAddresses(apiUrl, apiKey, customerID)
+	if err != nil {
+		return report, fmt.Errorf("failed to list addresses of customer %d: %w", customerID, err)
+	}
+
+	email := domain.AnonymizedEmail(customerID)
+	for _, address := range addresses {
+		if mode == domain.ErasureDelete {
+			err = s.repo.DeleteCustomerAddress(apiUrl, apiKey, customerID, address.ID)
+		} else {
+			err = s.repo.UpdateCustomerAddressFields(apiUrl, apiKey, customerID, address.ID, address.AnonymizedFields(email))
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to erase address %d of customer %d: %w", address.ID, customerID, err)
+		}
+		report.ErasedAddressIDs = append(report.ErasedAddressIDs, address.ID)
+	}
+
+	if mode == domain.ErasureDelete {
+		err = s.repo.DeleteCustomer(apiUrl, apiKey, customerID)
+	} else {
+		err = s.repo.UpdateCustomerFields(apiUrl, apiKey, customerID, domain.AnonymizedCustomerUpdate(customerID).Fields())
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to erase customer %d: %w", customerID, err)
+	}
+	report.CustomerErased = true
+
+	for i := range orders {
+		if fields := orders[i].PersonalDataFields(); len(fields) > 0 {
+			report.OrdersWithPersonalData = append(report.OrdersWithPersonalData, domain.OrderPersonalData{
+				OrderID: orders[i].ID,
+				Fields:  fields,
+			})
+		}
+	}
+
+	fmt.Printf("[ERASURE] Cliente %d borrado: %d direcciones, %d órdenes con datos personales\n",
+		customerID, len(report.ErasedAddressIDs), len(report.OrdersWithPersonalData))
+	return report, nil
+}
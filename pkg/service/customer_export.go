@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+// ExportCustomerData collects the customer profile, addresses, cart, wishlist
+// and orders into a single bundle. Use WriteJSON or WriteCSV on the result to
+// produce the file handed to the customer.
+func (s *ecommerceService) ExportCustomerData(ctx context.Context, apiUrl, apiKey string, customerID int) (*domain.CustomerDataExport, error) {
+	fmt.Printf("[EXPORT] Exportando datos del cliente %d\n", customerID)
+
+	customer, err := s.GetCustomerByID(ctx, strconv.Itoa(customerID), apiUrl, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer %d: %w", customerID, err)
+	}
+
+	addresses, err := s.repo.ListCustomerAddresses(apiUrl, apiKey, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses of customer %d: %w", customerID, err)
+	}
+
+	cartItems, err := s.repo.ListShoppingCartItems(apiUrl, apiKey, customerID, domain.ShoppingCartTypeCart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cart of customer %d: %w", customerID, err)
+	}
+
+	wishlistItems, err := s.repo.ListShoppingCartItems(apiUrl, apiKey, customerID, domain.ShoppingCartTypeWishlist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wishlist of customer %d: %w", customerID, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.repo.ListOrders(apiUrl, apiKey, domain.OrderQuery{CustomerID: customerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders of customer %d: %w", customerID, err)
+	}
+
+	return &domain.CustomerDataExport{
+		ExportedAt:    time.Now().UTC(),
+		Customer:      customer,
+		Addresses:     addresses,
+		CartItems:     cartItems,
+		WishlistItems: wishlistItems,
+		Orders:        orders,
+	}, nil
+}
@@ -3,12 +3,16 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
 )
 
 type EcommerceCredentials struct {
-	ApiURL  string
-	ApiKey  string
-	Context context.Context
+	ApiURL         string
+	ApiKey         string
+	DefaultCountry string
+	Context        context.Context
 }
 
 type IntegrationService interface {
@@ -52,7 +56,7 @@ func (s *ecommerceCredentialsService) GetCredentials(ctx context.Context, posID
 		return nil, fmt.Errorf("no active ecommerce integration found for posID: %s", posID)
 	}
 
-	var apiUrl, username, password string
+	var apiUrl, username, password, defaultCountry string
 	for _, cfg := range ecommerceIntegration.Configs {
 		switch cfg.Key {
 		case "apiUrl":
@@ -61,6 +65,8 @@ func (s *ecommerceCredentialsService) GetCredentials(ctx context.Context, posID
 			username = cfg.Value
 		case "password":
 			password = cfg.Value
+		case "defaultCountry":
+			defaultCountry = strings.ToUpper(strings.TrimSpace(cfg.Value))
 		}
 	}
 
@@ -68,6 +74,13 @@ func (s *ecommerceCredentialsService) GetCredentials(ctx context.Context, posID
 		return nil, fmt.Errorf("missing required ecommerce credentials")
 	}
 
+	if defaultCountry != "" {
+		if _, ok := domain.CountryCallingCode(defaultCountry); !ok {
+			return nil, fmt.Errorf("unsupported default country %q in ecommerce integration", defaultCountry)
+		}
+		ctx = WithDefaultCountry(ctx, defaultCountry)
+	}
+
 	tokenUrl := fmt.Sprintf("%s/token", apiUrl)
 	apiKey, err := s.ecommerceService.GetApiKey(ctx, username, password, tokenUrl)
 	if err != nil {
@@ -75,8 +88,9 @@ func (s *ecommerceCredentialsService) GetCredentials(ctx context.Context, posID
 	}
 
 	return &EcommerceCredentials{
-		ApiURL:  apiUrl,
-		ApiKey:  apiKey,
-		Context: ctx,
+		ApiURL:         apiUrl,
+		ApiKey:         apiKey,
+		DefaultCountry: defaultCountry,
+		Context:        ctx,
 	}, nil
 }
@@ -2,7 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
 	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/repository"
@@ -14,6 +19,7 @@ type EcommerceService interface {
 	GetItemsRaw(ctx context.Context, apiUrl, apiKey string, page, limit int, publishedStatus bool) ([]byte, error)
 	GetItemByID(ctx context.Context, id, apiUrl, apiKey string) (*domain.Item, error)
 	GetItemByIDWithDetails(ctx context.Context, id, apiUrl, apiKey string) (*domain.ItemDetails, error)
+	GetProduct(ctx context.Context, id, apiUrl, apiKey string) (*domain.Product, error)
 	GetItemByIDRaw(ctx context.Context, id, apiUrl, apiKey string) ([]byte, error)
 	GetCustomers(ctx context.Context, apiUrl, apiKey string) ([]domain.Customer, error)
 	GetAllCustomers(ctx context.Context, apiUrl, apiKey string) ([]domain.Customer, error)
@@ -28,20 +34,76 @@ type EcommerceService interface {
 	CreateEcommerceShippingAddress(ctx context.Context, apiUrl, apiKey string, customerID int, addressData []byte) ([]byte, error)
 	DeleteEcommerceShoppingCart(ctx context.Context, apiUrl, apiKey string, customerID int) error
 	CreateEcommerceShoppingCartItem(ctx context.Context, apiUrl, apiKey string, cartItemData []byte) ([]byte, error)
-	CreateEcommerceOrder(ctx context.Context, apiUrl, apiKey string, orderData []byte) ([]byte, error)
+	CreateEcommerceOrder(ctx context.Context, apiUrl, apiKey string, orderData []byte, opts ...OrderOption) ([]byte, error)
 	CountEcommerceItems(ctx context.Context, apiUrl, apiKey string, filters map[string]string) (int64, error)
 	UpdateOrderItemPrice(ctx context.Context, apiUrl, apiKey string, orderID, itemID int, orderItemData []byte) error
 	UpdateOrder(ctx context.Context, apiUrl, apiKey string, orderID int, orderData []byte) error
 	GetOrderByID(ctx context.Context, apiUrl, apiKey string, orderID int) ([]byte, error)
+	FindCustomerByEmail(ctx context.Context, apiUrl, apiKey, email string) (*domain.Customer, error)
+	EnsureCustomer(ctx context.Context, apiUrl, apiKey string, input domain.CustomerInput) (*domain.Customer, bool, error)
+	ListOrders(ctx context.Context, apiUrl, apiKey string, query domain.OrderQuery) ([]domain.Order, error)
+	GetOrder(ctx context.Context, apiUrl, apiKey string, orderID int) (*domain.Order, error)
+	MarkOrderPaid(ctx context.Context, apiUrl, apiKey string, orderID int) (*domain.Order, error)
+	MarkOrderShipped(ctx context.Context, apiUrl, apiKey string, orderID int, trackingNumber string) (*domain.Order, error)
+	CancelOrder(ctx context.Context, apiUrl, apiKey string, orderID int, reason string) (*domain.Order, error)
+	CompleteOrder(ctx context.Context, apiUrl, apiKey string, orderID int) (*domain.Order, error)
+	RefundOrder(ctx context.Context, apiUrl, apiKey string, orderID int, opts domain.RefundOptions) (*domain.RefundResult, error)
+	PartialRefund(ctx context.Context, apiUrl, apiKey string, orderID int, amount float64, opts domain.RefundOptions) (*domain.RefundResult, error)
+	CreateShipment(ctx context.Context, apiUrl, apiKey string, orderID int, trackingNumber, carrier string, items []domain.ShipmentItem) (*domain.Shipment, error)
+	ListOrderShipments(ctx context.Context, apiUrl, apiKey string, orderID int) ([]domain.Shipment, error)
+	MarkShipmentDelivered(ctx context.Context, apiUrl, apiKey string, shipmentID int) error
+	AddOrderNote(ctx context.Context, apiUrl, apiKey string, orderID int, note string, displayToCustomer bool) (*domain.OrderNote, error)
+	ListOrderNotes(ctx context.Context, apiUrl, apiKey string, orderID int) ([]domain.OrderNote, error)
+	SetOrderItemPrice(ctx context.Context, apiUrl, apiKey string, orderID, itemID int, unitPrice float64, opts domain.OrderItemPriceOptions) (*domain.Order, error)
+	SetOrderItemLineTotal(ctx context.Context, apiUrl, apiKey string, orderID, itemID int, lineTotal float64, opts domain.OrderItemPriceOptions) (*domain.Order, error)
+	ListCartItems(ctx context.Context, apiUrl, apiKey string, customerID int, cartType string) ([]domain.ShoppingCartItem, error)
+	AddCartItem(ctx context.Context, apiUrl, apiKey string, input domain.CartItemInput) (*domain.ShoppingCartItem, error)
+	UpdateCartItemQuantity(ctx context.Context, apiUrl, apiKey string, cartItemID, quantity int) (*domain.ShoppingCartItem, error)
+	RemoveCartItem(ctx context.Context, apiUrl, apiKey string, cartItemID int) error
+	ClearCart(ctx context.Context, apiUrl, apiKey string, customerID int, cartType string) error
+	ValidateCartItem(ctx context.Context, apiUrl, apiKey string, productID, quantity, reserved int) error
+	ListCustomerAddresses(ctx context.Context, apiUrl, apiKey string, customerID int) ([]domain.Address, error)
+	EnsureAddress(ctx context.Context, apiUrl, apiKey string, customerID int, address domain.Address, kind domain.AddressKind) (*domain.Address, bool, error)
+	UpdateAddress(ctx context.Context, apiUrl, apiKey string, customerID int, address domain.Address) (*domain.Address, error)
+	DeleteAddress(ctx context.Context, apiUrl, apiKey string, customerID, addressID int) error
+	SetDefaultAddress(ctx context.Context, apiUrl, apiKey string, customerID, addressID int, kind domain.AddressKind) error
+	UpdateCustomer(ctx context.Context, apiUrl, apiKey string, customerID int, update domain.CustomerUpdate) (*domain.Customer, error)
+	EraseCustomer(ctx context.Context, apiUrl, apiKey string, customerID int, mode domain.ErasureMode) (*domain.ErasureReport, error)
+	ExportCustomerData(ctx context.Context, apiUrl, apiKey string, customerID int) (*domain.CustomerDataExport, error)
+	AudienceExport(ctx context.Context, apiUrl, apiKey string, w io.Writer, opts AudienceExportOptions) (int, error)
+	ListCustomers(ctx context.Context, apiUrl, apiKey string, query domain.CustomerQuery) ([]domain.Customer, error)
+	ListCustomerRoles(ctx context.Context, apiUrl, apiKey string) ([]domain.CustomerRole, error)
+	FindDuplicateCustomers(ctx context.Context, apiUrl, apiKey string, opts DuplicateCustomerOptions) (*domain.DuplicateReport, error)
+	ListCountries(ctx context.Context, apiUrl, apiKey string) ([]domain.Country, error)
+	ListStates(ctx context.Context, apiUrl, apiKey string, countryID int) ([]domain.StateProvince, error)
+	FindCountry(ctx context.Context, apiUrl, apiKey, query string) (*domain.Country, error)
+	FindState(ctx context.Context, apiUrl, apiKey string, countryID int, query string) (*domain.StateProvince, error)
+	ResolveAddress(ctx context.Context, apiUrl, apiKey string, address domain.Address) (domain.Address, error)
 }
 
 type ecommerceService struct {
-	repo repository.EcommerceRepository
+	repo  repository.EcommerceRepository
+	locks *keyedMutex
 }
 
 func NewEcommerceService(repo repository.EcommerceRepository) EcommerceService {
 	return &ecommerceService{
-		repo: repo,
+		repo:  repo,
+		locks: newKeyedMutex(),
+	}
+}
+
+type OrderOption func(*orderOptions)
+
+type orderOptions struct {
+	idempotencyKey string
+}
+
+// WithIdempotencyKey makes order creation idempotent for the given key, such as
+// the auction ID: repeated calls with the same key return the original order.
+func WithIdempotencyKey(key string) OrderOption {
+	return func(o *orderOptions) {
+		o.idempotencyKey = key
 	}
 }
 
@@ -65,6 +127,10 @@ func (s *ecommerceService) GetItemByIDWithDetails(ctx context.Context, id, apiUr
 	return s.repo.GetItemByIDWithDetails(apiUrl, apiKey, id)
 }
 
+func (s *ecommerceService) GetProduct(ctx context.Context, id, apiUrl, apiKey string) (*domain.Product, error) {
+	return s.repo.GetProduct(apiUrl, apiKey, id)
+}
+
 func (s *ecommerceService) GetItemByIDRaw(ctx context.Context, id, apiUrl, apiKey string) ([]byte, error) {
 	return s.repo.GetItemByIDRaw(apiUrl, apiKey, id)
 }
@@ -78,7 +144,7 @@ func (s *ecommerceService) GetAllCustomers(ctx context.Context, apiUrl, apiKey s
 }
 
 func (s *ecommerceService) GetCustomerByID(ctx context.Context, id, apiUrl, apiKey string) (*domain.Customer, error) {
-	return s.repo.GetCustomerByID(id, apiUrl, apiKey)
+	return s.repo.GetCustomerByID(apiUrl, apiKey, id)
 }
 
 func (s *ecommerceService) GetOrderEmails(ctx context.Context, apiUrl, apiKey string) ([]string, error) {
@@ -102,6 +168,7 @@ func (s *ecommerceService) CountEcommerceItems(ctx context.Context, apiUrl, apiK
 }
 
 func (s *ecommerceService) CreateEcommerceCustomer(ctx context.Context, apiUrl, apiKey string, customerData []byte) ([]byte, error) {
+	customerData = normalizeRawPayload(ctx, customerData, "customer", "phone")
 	fmt.Printf("Creating customer in ecommerce with data: %s\n", string(customerData))
 
 	respBody, err := s.repo.CreateCustomer(apiUrl, apiKey, customerData)
@@ -113,6 +180,7 @@ func (s *ecommerceService) CreateEcommerceCustomer(ctx context.Context, apiUrl,
 }
 
 func (s *ecommerceService) CreateEcommerceBillingAddress(ctx context.Context, apiUrl, apiKey string, customerID int, addressData []byte) ([]byte, error) {
+	addressData = normalizeRawPayload(ctx, addressData, "address", "phone_number")
 	fmt.Printf("Creating billing address for customer %d in ecommerce with data: %s\n", customerID, string(addressData))
 
 	respBody, err := s.repo.CreateBillingAddress(apiUrl, apiKey, customerID, addressData)
@@ -124,6 +192,7 @@ func (s *ecommerceService) CreateEcommerceBillingAddress(ctx context.Context, ap
 }
 
 func (s *ecommerceService) CreateEcommerceShippingAddress(ctx context.Context, apiUrl, apiKey string, customerID int, addressData []byte) ([]byte, error) {
+	addressData = normalizeRawPayload(ctx, addressData, "address", "phone_number")
 	fmt.Printf("Creating shipping address for customer %d in ecommerce with data: %s\n", customerID, string(addressData))
 
 	respBody, err := s.repo.CreateShippingAddress(apiUrl, apiKey, customerID, addressData)
@@ -148,6 +217,19 @@ func (s *ecommerceService) DeleteEcommerceShoppingCart(ctx context.Context, apiU
 func (s *ecommerceService) CreateEcommerceShoppingCartItem(ctx context.Context, apiUrl, apiKey string, cartItemData []byte) ([]byte, error) {
 	fmt.Printf("Creating shopping cart item in ecommerce with data: %s\n", string(cartItemData))
 
+	item, err := domain.DecodeShoppingCartItem(cartItemData)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.Validate(); err != nil {
+		return nil, err
+	}
+	if item.ShoppingCartType == "" || item.ShoppingCartType == domain.ShoppingCartTypeCart {
+		if err := validateCartItem(s.repo, apiUrl, apiKey, item.ProductID, item.Quantity, 0); err != nil {
+			return nil, err
+		}
+	}
+
 	respBody, err := s.repo.CreateShoppingCartItem(apiUrl, apiKey, cartItemData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create shopping cart item: %w", err)
@@ -156,10 +238,26 @@ func (s *ecommerceService) CreateEcommerceShoppingCartItem(ctx context.Context,
 	return respBody, nil
 }
 
-func (s *ecommerceService) CreateEcommerceOrder(ctx context.Context, apiUrl, apiKey string, orderData []byte) ([]byte, error) {
+func (s *ecommerceService) CreateEcommerceOrder(ctx context.Context, apiUrl, apiKey string, orderData []byte, opts ...OrderOption) ([]byte, error) {
 	fmt.Printf("Creating order in ecommerce with data: %s\n", string(orderData))
 
-	respBody, err := s.repo.CreateOrder(apiUrl, apiKey, orderData)
+	var options orderOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.idempotencyKey == "" {
+		respBody, err := s.repo.CreateOrder(apiUrl, apiKey, orderData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create order: %w", err)
+		}
+		return respBody, nil
+	}
+
+	unlock := s.locks.Lock("order-idempotency:" + options.idempotencyKey)
+	defer unlock()
+
+	respBody, err := s.repo.CreateOrderIdempotent(apiUrl, apiKey, orderData, options.idempotencyKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
@@ -210,3 +308,624 @@ func (s *ecommerceService) GetOrderByID(ctx context.Context, apiUrl, apiKey stri
 
 	return respBody, nil
 }
+
+func (s *ecommerceService) FindCustomerByEmail(ctx context.Context, apiUrl, apiKey, email string) (*domain.Customer, error) {
+	return s.repo.FindCustomerByEmail(apiUrl, apiKey, email)
+}
+
+func (s *ecommerceService) ListCustomers(ctx context.Context, apiUrl, apiKey string, query domain.CustomerQuery) ([]domain.Customer, error) {
+	customers, err := s.repo.ListCustomers(apiUrl, apiKey, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customers: %w", err)
+	}
+	return customers, nil
+}
+
+func (s *ecommerceService) ListCustomerRoles(ctx context.Context, apiUrl, apiKey string) ([]domain.CustomerRole, error) {
+	roles, err := s.repo.ListCustomerRoles(apiUrl, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer roles: %w", err)
+	}
+	return roles, nil
+}
+
+// EnsureCustomer returns the customer with the input email, creating it when it
+// does not exist yet. The boolean result reports whether it was created.
+func (s *ecommerceService) EnsureCustomer(ctx context.Context, apiUrl, apiKey string, input domain.CustomerInput) (*domain.Customer, bool, error) {
+	unlock := s.locks.Lock("customer:" + domain.NormalizeEmail(input.Email))
+	defer unlock()
+
+	return ensureCustomer(ctx, s.repo, apiUrl, apiKey, input)
+}
+
+func ensureCustomer(ctx context.Context, repo repository.EcommerceRepository, apiUrl, apiKey string, input domain.CustomerInput) (*domain.Customer, bool, error) {
+	input.Email = domain.NormalizeEmail(input.Email)
+	input.Phone = normalizePhone(ctx, input.Phone)
+
+	customer, err := repo.FindCustomerByEmail(apiUrl, apiKey, input.Email)
+	if err == nil {
+		fmt.Printf("Customer %s already exists in ecommerce with ID %d\n", input.Email, customer.ID)
+		return customer, false, nil
+	}
+	if !errors.Is(err, domain.ErrCustomerNotFound) {
+		return nil, false, fmt.Errorf("failed to find customer by email: %w", err)
+	}
+
+	fmt.Printf("Creating customer %s in ecommerce\n", input.Email)
+	customer, err = repo.CreateCustomerFromInput(apiUrl, apiKey, input)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	return customer, true, nil
+}
+
+func (s *ecommerceService) UpdateCustomer(ctx context.Context, apiUrl, apiKey string, customerID int, update domain.CustomerUpdate) (*domain.Customer, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	if update.Phone != nil && *update.Phone != "" {
+		phone := normalizePhone(ctx, *update.Phone)
+		update.Phone = &phone
+	}
+
+	if update.Email != nil {
+		email := domain.NormalizeEmail(*update.Email)
+		unlock := s.locks.Lock("customer:" + email)
+		defer unlock()
+
+		existing, err := s.repo.FindCustomerByEmail(apiUrl, apiKey, email)
+		if err == nil && existing.ID != customerID {
+			return nil, fmt.Errorf("%w: %s belongs to customer %d", domain.ErrCustomerEmailTaken, email, existing.ID)
+		}
+		if err != nil && !errors.Is(err, domain.ErrCustomerNotFound) {
+			return nil, fmt.Errorf("failed to find customer by email: %w", err)
+		}
+	}
+
+	fmt.Printf("Updating customer %d in ecommerce\n", customerID)
+
+	if err := s.repo.UpdateCustomerFields(apiUrl, apiKey, customerID, update.Fields()); err != nil {
+		return nil, fmt.Errorf("failed to update customer %d: %w", customerID, err)
+	}
+
+	customer, err := s.repo.GetCustomerByID(apiUrl, apiKey, strconv.Itoa(customerID))
+	if err != nil {
+		return nil, fmt.Errorf("customer %d updated but failed to read it back: %w", customerID, err)
+	}
+	return customer, nil
+}
+
+func (s *ecommerceService) ListOrders(ctx context.Context, apiUrl, apiKey string, query domain.OrderQuery) ([]domain.Order, error) {
+	orders, err := s.repo.ListOrders(apiUrl, apiKey, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+func (s *ecommerceService) GetOrder(ctx context.Context, apiUrl, apiKey string, orderID int) (*domain.Order, error) {
+	order, err := s.repo.GetOrder(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order by ID: %w", err)
+	}
+
+	return order, nil
+}
+
+func (s *ecommerceService) MarkOrderPaid(ctx context.Context, apiUrl, apiKey string, orderID int) (*domain.Order, error) {
+	return s.transitionOrder(apiUrl, apiKey, orderID, domain.OrderTransitionMarkPaid, func(order *domain.Order) error {
+		return order.MarkPaid()
+	})
+}
+
+func (s *ecommerceService) MarkOrderShipped(ctx context.Context, apiUrl, apiKey string, orderID int, trackingNumber string) (*domain.Order, error) {
+	return s.transitionOrder(apiUrl, apiKey, orderID, domain.OrderTransitionMarkShipped, func(order *domain.Order) error {
+		return order.MarkShipped(trackingNumber)
+	})
+}
+
+func (s *ecommerceService) CancelOrder(ctx context.Context, apiUrl, apiKey string, orderID int, reason string) (*domain.Order, error) {
+	return s.transitionOrder(apiUrl, apiKey, orderID, domain.OrderTransitionCancel, func(order *domain.Order) error {
+		return order.Cancel(reason)
+	})
+}
+
+func (s *ecommerceService) CompleteOrder(ctx context.Context, apiUrl, apiKey string, orderID int) (*domain.Order, error) {
+	return s.transitionOrder(apiUrl, apiKey, orderID, domain.OrderTransitionComplete, func(order *domain.Order) error {
+		return order.Complete()
+	})
+}
+
+// transitionOrder validates the transition against the current order state
+// before sending only the status fields and custom values to the API.
+func (s *ecommerceService) transitionOrder(apiUrl, apiKey string, orderID int, transition domain.OrderTransition, apply func(order *domain.Order) error) (*domain.Order, error) {
+	unlock := s.locks.Lock(fmt.Sprintf("order:%d", orderID))
+	defer unlock()
+
+	order, err := s.repo.GetOrder(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order by ID: %w", err)
+	}
+
+	if err := apply(order); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Applying %s to order %d (order: %s, payment: %s, shipping: %s)\n", transition, orderID, order.OrderStatus, order.PaymentStatus, order.ShippingStatus)
+
+	fields := map[string]interface{}{
+		"order_status":    order.OrderStatus,
+		"payment_status":  order.PaymentStatus,
+		"shipping_status": order.ShippingStatus,
+	}
+	if len(order.CustomValues) > 0 {
+		fields["custom_values"] = order.CustomValues
+	}
+
+	if err := s.repo.UpdateOrderFields(apiUrl, apiKey, orderID, fields); err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	return order, nil
+}
+
+func (s *ecommerceService) RefundOrder(ctx context.Context, apiUrl, apiKey string, orderID int, opts domain.RefundOptions) (*domain.RefundResult, error) {
+	return s.refundOrder(apiUrl, apiKey, orderID, 0, opts)
+}
+
+func (s *ecommerceService) PartialRefund(ctx context.Context, apiUrl, apiKey string, orderID int, amount float64, opts domain.RefundOptions) (*domain.RefundResult, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: %.2f must be positive", domain.ErrInvalidRefundAmount, amount)
+	}
+	return s.refundOrder(apiUrl, apiKey, orderID, amount, opts)
+}
+
+func (s *ecommerceService) refundOrder(apiUrl, apiKey string, orderID int, amount float64, opts domain.RefundOptions) (*domain.RefundResult, error) {
+	unlock := s.locks.Lock(fmt.Sprintf("order:%d", orderID))
+	defer unlock()
+
+	order, err := s.repo.GetOrder(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order by ID: %w", err)
+	}
+
+	refund, err := order.NewRefundRequest(amount, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Refunding %.2f of order %d (partial: %t, restock: %t)\n", refund.Amount, orderID, refund.IsPartial, refund.Restock)
+
+	if err := s.repo.RefundOrder(apiUrl, apiKey, orderID, refund); err != nil {
+		return nil, fmt.Errorf("failed to refund order: %w", err)
+	}
+
+	updated, err := s.repo.GetOrder(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("order refunded but failed to read it back: %w", err)
+	}
+
+	return &domain.RefundResult{
+		OrderID:         orderID,
+		RequestedAmount: refund.Amount,
+		RefundedAmount:  updated.RefundedAmount,
+		OrderTotal:      updated.OrderTotal,
+		PaymentStatus:   updated.PaymentStatus,
+	}, nil
+}
+
+func (s *ecommerceService) CreateShipment(ctx context.Context, apiUrl, apiKey string, orderID int, trackingNumber, carrier string, items []domain.ShipmentItem) (*domain.Shipment, error) {
+	unlock := s.locks.Lock(fmt.Sprintf("order:%d", orderID))
+	defer unlock()
+
+	order, err := s.repo.GetOrder(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order by ID: %w", err)
+	}
+
+	existing, err := s.repo.ListOrderShipments(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order shipments: %w", err)
+	}
+
+	shipment, err := order.NewShipment(trackingNumber, carrier, items, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Creating shipment for order %d with tracking number %q (%s)\n", orderID, trackingNumber, carrier)
+
+	created, err := s.repo.CreateShipment(apiUrl, apiKey, *shipment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shipment: %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *ecommerceService) ListOrderShipments(ctx context.Context, apiUrl, apiKey string, orderID int) ([]domain.Shipment, error) {
+	shipments, err := s.repo.ListOrderShipments(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order shipments: %w", err)
+	}
+
+	return shipments, nil
+}
+
+func (s *ecommerceService) MarkShipmentDelivered(ctx context.Context, apiUrl, apiKey string, shipmentID int) error {
+	fmt.Printf("Marking shipment %d as delivered\n", shipmentID)
+
+	if err := s.repo.MarkShipmentDelivered(apiUrl, apiKey, shipmentID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to mark shipment as delivered: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ecommerceService) AddOrderNote(ctx context.Context, apiUrl, apiKey string, orderID int, note string, displayToCustomer bool) (*domain.OrderNote, error) {
+	if note == "" {
+		return nil, fmt.Errorf("order note cannot be empty")
+	}
+
+	fmt.Printf("Adding note to order %d (visible to customer: %t)\n", orderID, displayToCustomer)
+
+	created, err := s.repo.AddOrderNote(apiUrl, apiKey, domain.OrderNote{
+		OrderID:           orderID,
+		Note:              note,
+		DisplayToCustomer: displayToCustomer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add order note: %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *ecommerceService) ListOrderNotes(ctx context.Context, apiUrl, apiKey string, orderID int) ([]domain.OrderNote, error) {
+	notes, err := s.repo.ListOrderNotes(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// SetOrderItemPrice overrides the unit price of an order item, for example with
+// the winning bid, recomputes the order totals and verifies the result by
+// reading the order back.
+func (s *ecommerceService) SetOrderItemPrice(ctx context.Context, apiUrl, apiKey string, orderID, itemID int, unitPrice float64, opts domain.OrderItemPriceOptions) (*domain.Order, error) {
+	unlock := s.locks.Lock(fmt.Sprintf("order:%d", orderID))
+	defer unlock()
+
+	return setOrderItemPrice(s.repo, apiUrl, apiKey, orderID, itemID, opts, func(order *domain.Order) (*domain.OrderItem, error) {
+		return order.SetItemUnitPrice(itemID, unitPrice, opts.TaxRate)
+	})
+}
+
+// SetOrderItemLineTotal works like SetOrderItemPrice but sets the line price of
+// the item to lineTotal exactly, e.g. a winning bid for several units that
+// does not divide evenly by the quantity.
+func (s *ecommerceService) SetOrderItemLineTotal(ctx context.Context, apiUrl, apiKey string, orderID, itemID int, lineTotal float64, opts domain.OrderItemPriceOptions) (*domain.Order, error) {
+	unlock := s.locks.Lock(fmt.Sprintf("order:%d", orderID))
+	defer unlock()
+
+	return setOrderItemLineTotal(s.repo, apiUrl, apiKey, orderID, itemID, lineTotal, opts)
+}
+
+func setOrderItemLineTotal(repo repository.EcommerceRepository, apiUrl, apiKey string, orderID, itemID int, lineTotal float64, opts domain.OrderItemPriceOptions) (*domain.Order, error) {
+	return setOrderItemPrice(repo, apiUrl, apiKey, orderID, itemID, opts, func(order *domain.Order) (*domain.OrderItem, error) {
+		return order.SetItemLineTotal(itemID, lineTotal, opts.TaxRate)
+	})
+}
+
+func setOrderItemPrice(repo repository.EcommerceRepository, apiUrl, apiKey string, orderID, itemID int, opts domain.OrderItemPriceOptions, apply func(order *domain.Order) (*domain.OrderItem, error)) (*domain.Order, error) {
+	expected, err := repo.GetOrder(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order by ID: %w", err)
+	}
+
+	item, err := apply(expected)
+	if err != nil {
+		return nil, err
+	}
+	expected.RecalculateTotals()
+
+	fmt.Printf("Setting order %d item %d unit price to %.2f (order total %.2f)\n", orderID, itemID, item.UnitPriceExclTax, expected.OrderTotal)
+
+	if err := repo.UpdateOrderItem(apiUrl, apiKey, orderID, *item); err != nil {
+		return nil, fmt.Errorf("failed to update order item price: %w", err)
+	}
+
+	if opts.Mode == domain.OrderTotalsExplicit {
+		err := repo.UpdateOrderFields(apiUrl, apiKey, orderID, map[string]interface{}{
+			"order_subtotal_excl_tax": expected.OrderSubtotalExclTax,
+			"order_subtotal_incl_tax": expected.OrderSubtotalInclTax,
+			"order_tax":               expected.OrderTax,
+			"order_total":             expected.OrderTotal,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update order totals: %w", err)
+		}
+	}
+
+	updated, err := repo.GetOrder(apiUrl, apiKey, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order back: %w", err)
+	}
+
+	if opts.Mode == domain.OrderTotalsExplicit {
+		if err := updated.VerifyItemPrice(expected, itemID, true); err != nil {
+			return updated, err
+		}
+		return updated, nil
+	}
+
+	if err := updated.VerifyItemPrice(expected, itemID, false); err != nil {
+		return updated, err
+	}
+	recalculated := *updated
+	recalculated.RecalculateTotals()
+	if err := updated.VerifyItemPrice(&recalculated, itemID, true); err != nil {
+		return updated, err
+	}
+
+	return updated, nil
+}
+
+func (s *ecommerceService) ListCartItems(ctx context.Context, apiUrl, apiKey string, customerID int, cartType string) ([]domain.ShoppingCartItem, error) {
+	cartType, err := domain.NormalizeShoppingCartType(cartType)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.ListShoppingCartItems(apiUrl, apiKey, customerID, cartType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s items: %w", cartType, err)
+	}
+
+	return items, nil
+}
+
+func (s *ecommerceService) AddCartItem(ctx context.Context, apiUrl, apiKey string, input domain.CartItemInput) (*domain.ShoppingCartItem, error) {
+	item, err := input.ToShoppingCartItem()
+	if err != nil {
+		return nil, err
+	}
+
+	if item.ShoppingCartType == domain.ShoppingCartTypeCart {
+		if err := validateCartItem(s.repo, apiUrl, apiKey, item.ProductID, item.Quantity, input.ReservedQuantity); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Printf("Adding %d x product %d to %s of customer %d\n", item.Quantity, item.ProductID, item.ShoppingCartType, item.CustomerID)
+
+	created, err := s.repo.AddShoppingCartItem(apiUrl, apiKey, item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shopping cart item: %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *ecommerceService) ValidateCartItem(ctx context.Context, apiUrl, apiKey string, productID, quantity, reserved int) error {
+	return validateCartItem(s.repo, apiUrl, apiKey, productID, quantity, reserved)
+}
+
+// validateCartItem checks the product against the constraints the remote cart
+// enforces and returns a *domain.CartValidationError listing every violation.
+func validateCartItem(repo repository.EcommerceRepository, apiUrl, apiKey string, productID, quantity, reserved int) error {
+	itemRef := domain.NewEcommerceItemRef(productID)
+
+	details, err := repo.GetItemByIDWithDetails(apiUrl, apiKey, itemRef.String())
+	if err != nil {
+		return fmt.Errorf("failed to get item details for %s: %w", itemRef, err)
+	}
+
+	issues := details.ValidateCartQuantity(quantity, reserved)
+	if len(issues) > 0 {
+		return &domain.CartValidationError{ItemRef: itemRef, Quantity: quantity, Issues: issues}
+	}
+	return nil
+}
+
+func (s *ecommerceService) UpdateCartItemQuantity(ctx context.Context, apiUrl, apiKey string, cartItemID, quantity int) (*domain.ShoppingCartItem, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be positive, got %d", quantity)
+	}
+
+	fmt.Printf("Updating shopping cart item %d quantity to %d\n", cartItemID, quantity)
+
+	updated, err := s.repo.UpdateShoppingCartItemQuantity(apiUrl, apiKey, cartItemID, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update shopping cart item: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (s *ecommerceService) RemoveCartItem(ctx context.Context, apiUrl, apiKey string, cartItemID int) error {
+	fmt.Printf("Removing shopping cart item %d\n", cartItemID)
+
+	if err := s.repo.DeleteShoppingCartItem(apiUrl, apiKey, cartItemID); err != nil {
+		return fmt.Errorf("failed to remove shopping cart item: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ecommerceService) ClearCart(ctx context.Context, apiUrl, apiKey string, customerID int, cartType string) error {
+	cartType, err := domain.NormalizeShoppingCartType(cartType)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Clearing %s for customer %d\n", cartType, customerID)
+
+	if err := s.repo.ClearShoppingCart(apiUrl, apiKey, customerID, cartType); err != nil {
+		return fmt.Errorf("failed to clear %s: %w", cartType, err)
+	}
+
+	return nil
+}
+
+func (s *ecommerceService) ListCustomerAddresses(ctx context.Context, apiUrl, apiKey string, customerID int) ([]domain.Address, error) {
+	addresses, err := s.repo.ListCustomerAddresses(apiUrl, apiKey, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses of customer %d: %w", customerID, err)
+	}
+	return addresses, nil
+}
+
+// EnsureAddress returns the customer's existing address at the same location,
+// or creates it as a billing or shipping address. The boolean reports whether
+// the address was created.
+func (s *ecommerceService) EnsureAddress(ctx context.Context, apiUrl, apiKey string, customerID int, address domain.Address, kind domain.AddressKind) (*domain.Address, bool, error) {
+	return ensureAddress(ctx, s.repo, apiUrl, apiKey, customerID, address, kind)
+}
+
+func ensureAddress(ctx context.Context, repo repository.EcommerceRepository, apiUrl, apiKey string, customerID int, address domain.Address, kind domain.AddressKind) (*domain.Address, bool, error) {
+	if err := kind.Validate(); err != nil {
+		return nil, false, err
+	}
+	address, err := resolveAddress(ctx, repo, apiUrl, apiKey, address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	addresses, err := repo.ListCustomerAddresses(apiUrl, apiKey, customerID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list addresses of customer %d: %w", customerID, err)
+	}
+	if existing, ok := domain.FindSameLocation(addresses, address); ok {
+		fmt.Printf("Reusing address %d of customer %d as %s address\n", existing.ID, customerID, kind)
+		return existing, false, nil
+	}
+
+	address.ID = 0
+	var created *domain.Address
+	if kind == domain.AddressKindBilling {
+		created, err = repo.AddCustomerBillingAddress(apiUrl, apiKey, customerID, address)
+	} else {
+		created, err = repo.AddCustomerShippingAddress(apiUrl, apiKey, customerID, address)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create %s address: %w", kind, err)
+	}
+	return created, true, nil
+}
+
+func (s *ecommerceService) UpdateAddress(ctx context.Context, apiUrl, apiKey string, customerID int, address domain.Address) (*domain.Address, error) {
+	if address.ID == 0 {
+		return nil, fmt.Errorf("address ID cannot be empty")
+	}
+
+	address, err := resolveAddress(ctx, s.repo, apiUrl, apiKey, address)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Updating address %d of customer %d\n", address.ID, customerID)
+
+	updated, err := s.repo.UpdateCustomerAddress(apiUrl, apiKey, customerID, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update address %d: %w", address.ID, err)
+	}
+	return updated, nil
+}
+
+func (s *ecommerceService) DeleteAddress(ctx context.Context, apiUrl, apiKey string, customerID, addressID int) error {
+	fmt.Printf("Deleting address %d of customer %d\n", addressID, customerID)
+
+	if err := s.repo.DeleteCustomerAddress(apiUrl, apiKey, customerID, addressID); err != nil {
+		return fmt.Errorf("failed to delete address %d: %w", addressID, err)
+	}
+	return nil
+}
+
+func (s *ecommerceService) SetDefaultAddress(ctx context.Context, apiUrl, apiKey string, customerID, addressID int, kind domain.AddressKind) error {
+	fmt.Printf("Setting address %d as default %s address of customer %d\n", addressID, kind, customerID)
+
+	if err := s.repo.SetDefaultCustomerAddress(apiUrl, apiKey, customerID, addressID, kind); err != nil {
+		return fmt.Errorf("failed to set default %s address: %w", kind, err)
+	}
+	return nil
+}
+
+func (s *ecommerceService) ListCountries(ctx context.Context, apiUrl, apiKey string) ([]domain.Country, error) {
+	countries, err := s.repo.ListCountries(apiUrl, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list countries: %w", err)
+	}
+	return countries, nil
+}
+
+func (s *ecommerceService) ListStates(ctx context.Context, apiUrl, apiKey string, countryID int) ([]domain.StateProvince, error) {
+	states, err := s.repo.ListStates(apiUrl, apiKey, countryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list states of country %d: %w", countryID, err)
+	}
+	return states, nil
+}
+
+func (s *ecommerceService) FindCountry(ctx context.Context, apiUrl, apiKey, query string) (*domain.Country, error) {
+	countries, err := s.ListCountries(ctx, apiUrl, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	country, ok := domain.FindCountry(countries, query)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCountryNotFound, query)
+	}
+	return country, nil
+}
+
+func (s *ecommerceService) FindState(ctx context.Context, apiUrl, apiKey string, countryID int, query string) (*domain.StateProvince, error) {
+	states, err := s.ListStates(ctx, apiUrl, apiKey, countryID)
+	if err != nil {
+		return nil, err
+	}
+	state, ok := domain.FindState(states, query)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrStateNotFound, query)
+	}
+	return state, nil
+}
+
+// ResolveAddress normalizes the address and resolves its country and state
+// text, e.g. "MX" and "Jalisco", to the store's IDs.
+func (s *ecommerceService) ResolveAddress(ctx context.Context, apiUrl, apiKey string, address domain.Address) (domain.Address, error) {
+	address = normalizeAddress(ctx, address)
+	return s.repo.ResolveAddressRegion(apiUrl, apiKey, address)
+}
+
+// resolveAddress is used before sending an address. An unknown country or
+// state is an error when the caller supplied it; one that came from parsing a
+// free-text address is dropped when unknown, since the address is still
+// deliverable without it.
+func resolveAddress(ctx context.Context, repo repository.EcommerceRepository, apiUrl, apiKey string, address domain.Address) (domain.Address, error) {
+	explicitCountry := address.CountryID != 0 || strings.TrimSpace(address.Country) != ""
+	explicitState := address.StateProvinceID != 0 || strings.TrimSpace(address.StateProvince) != ""
+	address = normalizeAddress(ctx, address)
+
+	resolved, err := repo.ResolveAddressRegion(apiUrl, apiKey, address)
+	if errors.Is(err, domain.ErrCountryNotFound) && !explicitCountry {
+		fmt.Printf("[ADDRESS] País %q no encontrado, se omite: %v\n", address.Country, err)
+		address.Country = ""
+		if !explicitState {
+			address.StateProvince = ""
+		}
+		return address, nil
+	}
+	if errors.Is(err, domain.ErrStateNotFound) && !explicitState {
+		fmt.Printf("[ADDRESS] Estado %q no encontrado, se omite: %v\n", address.StateProvince, err)
+		resolved.StateProvince = ""
+		return resolved, nil
+	}
+	if err != nil {
+		return address, fmt.Errorf("failed to resolve address region: %w", err)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+type defaultCountryContextKey struct{}
+
+// WithDefaultCountry sets the ISO alpha-2 country of the store the calls made
+// with the returned context target. Phone numbers without an international
+// prefix are formatted as E.164 numbers of that country. GetCredentials sets
+// it from the store's integration settings; use this to override it.
+func WithDefaultCountry(ctx context.Context, countryISO string) context.Context {
+	return context.WithValue(ctx, defaultCountryContextKey{}, countryISO)
+}
+
+func defaultCountryFromContext(ctx context.Context) string {
+	country, _ := ctx.Value(defaultCountryContextKey{}).(string)
+	return country
+}
+
+// normalizePhone formats the phone as E.164, keeping it as entered when it
+// cannot be parsed so that bad input never blocks a checkout.
+func normalizePhone(ctx context.Context, phone string) string {
+	normalized, err := domain.NormalizePhoneE164(phone, defaultCountryFromContext(ctx))
+	if err != nil {
+		fmt.Printf("[NORMALIZE] Teléfono %q sin normalizar: %v\n", phone, err)
+		return phone
+	}
+	return normalized
+}
+
+func normalizeAddress(ctx context.Context, address domain.Address) domain.Address {
+	return address.Normalized(defaultCountryFromContext(ctx))
+}
+
+// normalizeRawPayload normalizes the phone and email of a raw payload, either
+// wrapped as {"<key>": {...}} or bare. Only those two fields are rewritten;
+// every other field is passed through as it was sent. Payloads it cannot
+// decode are returned unchanged.
+func normalizeRawPayload(ctx context.Context, data []byte, key, phoneField string) []byte {
+	var wrapped map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return data
+	}
+
+	inner, isWrapped := wrapped[key]
+	if !isWrapped {
+		inner = data
+	}
+
+	var entity map[string]json.RawMessage
+	if err := json.Unmarshal(inner, &entity); err != nil || entity == nil {
+		return data
+	}
+
+	normalizeField := func(field string, normalize func(string) string) {
+		var value string
+		if err := json.Unmarshal(entity[field], &value); err != nil || value == "" {
+			return
+		}
+		if encoded, err := json.Marshal(normalize(value)); err == nil {
+			entity[field] = encoded
+		}
+	}
+	normalizeField(phoneField, func(phone string) string { return normalizePhone(ctx, phone) })
+	normalizeField("email", domain.NormalizeEmail)
+
+	normalized, err := json.Marshal(entity)
+	if err != nil {
+		return data
+	}
+	if !isWrapped {
+		return normalized
+	}
+
+	wrapped[key] = normalized
+	envelope, err := json.Marshal(wrapped)
+	if err != nil {
+		return data
+	}
+	return envelope
+}
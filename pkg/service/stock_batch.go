@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+)
+
+const defaultStockBatchConcurrency = 4
+
+var ErrStockBatchAborted = errors.New("stock batch aborted after an earlier failure")
+
+type StockBatchMode int
+
+const (
+	StockBatchBestEffort StockBatchMode = iota
+	StockBatchStopOnFirstError
+)
+
+type StockUpdate struct {
+	ItemRef  domain.ItemRef
+	Quantity int64
+	Reason   string
+}
+
+type StockBatchOptions struct {
+	Concurrency int
+	Mode        StockBatchMode
+}
+
+type StockUpdateResult struct {
+	ItemRef domain.ItemRef
+	Before  int64
+	After   int64
+	Err     error
+}
+
+func (r StockUpdateResult) Succeeded() bool {
+	return r.Err == nil
+}
+
+// StockBatchReport holds one result per update, in the same order as the input.
+type StockBatchReport struct {
+	Results   []StockUpdateResult
+	Succeeded int
+	Failed    int
+}
+
+func (r *StockBatchReport) Err() error {
+	var errs []error
+	for _, result := range r.Results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.ItemRef, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *stockService) UpdateStockBatch(ctx context.Context, apiUrl, apiKey string, updates []StockUpdate, opts StockBatchOptions) *StockBatchReport {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultStockBatchConcurrency
+	}
+
+	fmt.Printf("[STOCK_BATCH] Actualizando stock de %d productos (concurrencia: %d)\n", len(updates), concurrency)
+
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	report := &StockBatchReport{Results: make([]StockUpdateResult, len(updates))}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, update := range updates {
+		report.Results[i].ItemRef = update.ItemRef
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-batchCtx.Done():
+			report.Results[i].Err = s.batchAbortError(ctx)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, update StockUpdate) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			if batchCtx.Err() != nil {
+				report.Results[i].Err = s.batchAbortError(ctx)
+				return
+			}
+
+			before, after, err := s.applyStockUpdate(batchCtx, apiUrl, apiKey, update)
+			report.Results[i].Before = before
+			report.Results[i].After = after
+			report.Results[i].Err = err
+
+			if err != nil && opts.Mode == StockBatchStopOnFirstError {
+				cancel()
+			}
+		}(i, update)
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Results {
+		if result.Err != nil {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+	}
+
+	fmt.Printf("[STOCK_BATCH] Resultado: %d actualizados, %d con error\n", report.Succeeded, report.Failed)
+
+	return report
+}
+
+func (s *stockService) applyStockUpdate(ctx context.Context, apiUrl, apiKey string, update StockUpdate) (int64, int64, error) {
+	if err := update.ItemRef.Validate(); err != nil {
+		return 0, 0, err
+	}
+	if update.Quantity < 0 {
+		return 0, 0, fmt.Errorf("stock quantity cannot be negative, got %d", update.Quantity)
+	}
+
+	before, after, err := s.applyStockChange(ctx, apiUrl, apiKey, update.ItemRef, func(current int64) (int64, error) {
+		return update.Quantity, nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	reason := update.Reason
+	if reason == "" {
+		reason = "batch update"
+	}
+	s.recordLedgerEntry(ctx, update.ItemRef, before, after, reason)
+
+	return before, after, nil
+}
+
+// batchAbortError distinguishes a caller cancellation from the batch stopping
+// itself after a failure in stop-on-first-error mode.
+func (s *stockService) batchAbortError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return ErrStockBatchAborted
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/domain"
+	"github.com/Kivio-Product/Kivio.Product.Auctions.EcommerceClient/pkg/repository"
+)
+
+const (
+	defaultStockMaxAttempts = 3
+	stockRetryBackoff       = 200 * time.Millisecond
+)
+
+var (
+	ErrInsufficientStock    = errors.New("insufficient stock")
+	ErrStockConflict        = errors.New("stock was modified concurrently")
+	ErrReservationNotFound  = errors.New("stock reservation not found")
+	ErrReservationReleased  = errors.New("stock reservation already released")
+	ErrReservationCommitted = errors.New("stock reservation already committed")
+)
+
+type InsufficientStockError struct {
+	ItemRef   domain.ItemRef
+	Requested int64
+	Available int64
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for %s: requested %d, available %d", e.ItemRef, e.Requested, e.Available)
+}
+
+func (e *InsufficientStockError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+type StockService interface {
+	ReserveStock(ctx context.Context, apiUrl, apiKey, reservationID string, itemRef domain.ItemRef, quantity int64) (*domain.StockReservation, error)
+	ReleaseStock(ctx context.Context, apiUrl, apiKey, reservationID string) (*domain.StockReservation, error)
+	CommitStock(ctx context.Context, reservationID string) (*domain.StockReservation, error)
+	AdjustStock(ctx context.Context, apiUrl, apiKey string, itemRef domain.ItemRef, delta int64, reason string) (*domain.StockLedgerEntry, error)
+	GetStockLedger(ctx context.Context, itemRef domain.ItemRef) ([]domain.StockLedgerEntry, error)
+	UpdateStockBatch(ctx context.Context, apiUrl, apiKey string, updates []StockUpdate, opts StockBatchOptions) *StockBatchReport
+}
+
+type stockService struct {
+	repo         repository.EcommerceRepository
+	reservations repository.StockReservationStore
+	ledger       repository.StockLedgerStore
+	locks        *keyedMutex
+	maxAttempts  int
+}
+
+func NewStockService(repo repository.EcommerceRepository, reservations repository.StockReservationStore, ledger repository.StockLedgerStore) StockService {
+	return &stockService{
+		repo:         repo,
+		reservations: reservations,
+		ledger:       ledger,
+		locks:        newKeyedMutex(),
+		maxAttempts:  defaultStockMaxAttempts,
+	}
+}
+
+type actorContextKey struct{}
+
+// WithActor attaches the user or process responsible for stock changes made
+// with the returned context; it is recorded in the stock ledger.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+func actorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorContextKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return "system"
+}
+
+func (s *stockService) ReserveStock(ctx context.Context, apiUrl, apiKey, reservationID string, itemRef domain.ItemRef, quantity int64) (*domain.StockReservation, error) {
+	if reservationID == "" {
+		return nil, fmt.Errorf("reservation ID cannot be empty")
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("reservation quantity must be positive, got %d", quantity)
+	}
+	if err := itemRef.Validate(); err != nil {
+		return nil, err
+	}
+
+	unlock := s.locks.Lock("reservation:" + reservationID)
+	defer unlock()
+
+	existing, err := s.reservations.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reservation %s: %w", reservationID, err)
+	}
+	if existing != nil {
+		if existing.Status == domain.StockReservationCommitted {
+			return nil, fmt.Errorf("%w: %s", ErrReservationCommitted, reservationID)
+		}
+		if !existing.IsActive() {
+			return nil, fmt.Errorf("%w: %s", ErrReservationReleased, reservationID)
+		}
+		if existing.ItemRef != itemRef || existing.Quantity != quantity {
+			return nil, fmt.Errorf("reservation %s already exists for %d units of %s", reservationID, existing.Quantity, existing.ItemRef)
+		}
+		return existing, nil
+	}
+
+	fmt.Printf("[STOCK] Reservando %d unidades de %s (reserva %s)\n", quantity, itemRef, reservationID)
+
+	before, after, err := s.applyStockChange(ctx, apiUrl, apiKey, itemRef, func(current int64) (int64, error) {
+		if current < quantity {
+			return 0, &InsufficientStockError{ItemRef: itemRef, Requested: quantity, Available: current}
+		}
+		return current - quantity, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordLedgerEntry(ctx, itemRef, before, after, "reserve "+reservationID)
+
+	reservation := &domain.StockReservation{
+		ReservationID: reservationID,
+		ItemRef:       itemRef,
+		Quantity:      quantity,
+		Status:        domain.StockReservationReserved,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := s.reservations.SaveReservation(ctx, reservation); err != nil {
+		before, after, revertErr := s.applyStockChange(ctx, apiUrl, apiKey, itemRef, func(current int64) (int64, error) {
+			return current + quantity, nil
+		})
+		if revertErr != nil {
+			return nil, fmt.Errorf("failed to save reservation %s: %w (stock revert also failed: %v)", reservationID, err, revertErr)
+		}
+		s.recordLedgerEntry(ctx, itemRef, before, after, "revert reserve "+reservationID)
+		return nil, fmt.Errorf("failed to save reservation %s: %w", reservationID, err)
+	}
+
+	return reservation, nil
+}
+
+func (s *stockService) ReleaseStock(ctx context.Context, apiUrl, apiKey, reservationID string) (*domain.StockReservation, error) {
+	unlock := s.locks.Lock("reservation:" + reservationID)
+	defer unlock()
+
+	reservation, err := s.reservations.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reservation %s: %w", reservationID, err)
+	}
+	if reservation == nil {
+		return nil, fmt.Errorf("%w: %s", ErrReservationNotFound, reservationID)
+	}
+	if reservation.Status == domain.StockReservationCommitted {
+		return nil, fmt.Errorf("%w: %s", ErrReservationCommitted, reservationID)
+	}
+	if !reservation.IsActive() {
+		return reservation, nil
+	}
+
+	fmt.Printf("[STOCK] Liberando %d unidades de %s (reserva %s)\n", reservation.Quantity, reservation.ItemRef, reservationID)
+
+	before, after, err := s.applyStockChange(ctx, apiUrl, apiKey, reservation.ItemRef, func(current int64) (int64, error) {
+		return current + reservation.Quantity, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordLedgerEntry(ctx, reservation.ItemRef, before, after, "release "+reservationID)
+
+	releasedAt := time.Now().UTC()
+	reservation.Status = domain.StockReservationReleased
+	reservation.ReleasedAt = &releasedAt
+	if err := s.reservations.SaveReservation(ctx, reservation); err != nil {
+		return nil, fmt.Errorf("stock released but failed to save reservation %s: %w", reservationID, err)
+	}
+
+	return reservation, nil
+}
+
+// CommitStock marks a reservation as used by a sale. The reserved units stay
+// off the store's stock; unlike ReleaseStock nothing is given back.
+func (s *stockService) CommitStock(ctx context.Context, reservationID string) (*domain.StockReservation, error) {
+	unlock := s.locks.Lock("reservation:" + reservationID)
+	defer unlock()
+
+	reservation, err := s.reservations.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reservation %s: %w", reservationID, err)
+	}
+	if reservation == nil {
+		return nil, fmt.Errorf("%w: %s", ErrReservationNotFound, reservationID)
+	}
+	if reservation.Status == domain.StockReservationCommitted {
+		return reservation, nil
+	}
+	if !reservation.IsActive() {
+		return nil, fmt.Errorf("%w: %s", ErrReservationReleased, reservationID)
+	}
+
+	fmt.Printf("[STOCK] Confirmando reserva %s de %d unidades de %s\n", reservationID, reservation.Quantity, reservation.ItemRef)
+
+	committedAt := time.Now().UTC()
+	reservation.Status = domain.StockReservationCommitted
+	reservation.CommittedAt = &committedAt
+	if err := s.reservations.SaveReservation(ctx, reservation); err != nil {
+		return nil, fmt.Errorf("failed to save reservation %s: %w", reservationID, err)
+	}
+
+	return reservation, nil
+}
+
+func (s *stockService) AdjustStock(ctx context.Context, apiUrl, apiKey string, itemRef domain.ItemRef, delta int64, reason string) (*domain.StockLedgerEntry, error) {
+	if err := itemRef.Validate(); err != nil {
+		return nil, err
+	}
+	if delta == 0 {
+		return nil, fmt.Errorf("stock delta cannot be zero")
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("stock adjustment reason cannot be empty")
+	}
+
+	fmt.Printf("[STOCK] Ajustando stock de %s en %+d (%s)\n", itemRef, delta, reason)
+
+	before, after, err := s.applyStockChange(ctx, apiUrl, apiKey, itemRef, func(current int64) (int64, error) {
+		if current+delta < 0 {
+			return 0, &InsufficientStockError{ItemRef: itemRef, Requested: -delta, Available: current}
+		}
+		return current + delta, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := newStockLedgerEntry(ctx, itemRef, before, after, reason)
+	if err := s.ledger.AppendStockLedgerEntry(ctx, entry); err != nil {
+		return &entry, fmt.Errorf("stock adjusted but failed to record ledger entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (s *stockService) GetStockLedger(ctx context.Context, itemRef domain.ItemRef) ([]domain.StockLedgerEntry, error) {
+	return s.ledger.ListStockLedgerEntries(ctx, itemRef)
+}
+
+// recordLedgerEntry is used where the stock change already succeeded and the
+// caller's result must not depend on the audit trail being written.
+func (s *stockService) recordLedgerEntry(ctx context.Context, itemRef domain.ItemRef, before, after int64, reason string) {
+	entry := newStockLedgerEntry(ctx, itemRef, before, after, reason)
+	if err := s.ledger.AppendStockLedgerEntry(ctx, entry); err != nil {
+		fmt.Printf("[STOCK] ERROR al registrar movimiento de stock para %s: %v\n", itemRef, err)
+	}
+}
+
+func newStockLedgerEntry(ctx context.Context, itemRef domain.ItemRef, before, after int64, reason string) domain.StockLedgerEntry {
+	return domain.StockLedgerEntry{
+		ItemRef:   itemRef,
+		Before:    before,
+		After:     after,
+		Delta:     after - before,
+		Reason:    reason,
+		Actor:     actorFromContext(ctx),
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// applyStockChange serializes changes per item inside this process through the
+// keyed lock. The store has no compare-and-swap, so the stock is read again
+// right before UpdateItemStock and the change is recomputed, without writing,
+// when another process moved it in the meantime. ErrStockConflict is returned
+// once the attempts run out, and in that case nothing was written.
+//
+// Once written, the change is never applied again. If the stock read back
+// after the write differs, another writer landed on top of ours; the returned
+// before and after keep our delta but end at the value read back, so the
+// ledger matches the store.
+func (s *stockService) applyStockChange(ctx context.Context, apiUrl, apiKey string, itemRef domain.ItemRef, change func(current int64) (int64, error)) (int64, int64, error) {
+	unlock := s.locks.Lock("item:" + itemRef.String())
+	defer unlock()
+
+	current, err := s.currentStock(apiUrl, apiKey, itemRef)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+
+		target, err := change(current)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		fresh, err := s.currentStock(apiUrl, apiKey, itemRef)
+		if err != nil {
+			return 0, 0, err
+		}
+		if fresh != current {
+			fmt.Printf("[STOCK] Conflicto en %s (intento %d/%d): el stock cambió de %d a %d antes de escribir\n", itemRef, attempt, s.maxAttempts, current, fresh)
+			current = fresh
+			if err := sleepContext(ctx, time.Duration(attempt)*stockRetryBackoff); err != nil {
+				return 0, 0, err
+			}
+			continue
+		}
+
+		if err := s.repo.UpdateItemStock(apiUrl, apiKey, itemRef.String(), target); err != nil {
+			return 0, 0, fmt.Errorf("failed to update stock for %s: %w", itemRef, err)
+		}
+
+		verified, err := s.currentStock(apiUrl, apiKey, itemRef)
+		if err != nil {
+			fmt.Printf("[STOCK] No se pudo verificar el stock de %s tras escribir %d: %v\n", itemRef, target, err)
+			return current, target, nil
+		}
+		if verified != target {
+			fmt.Printf("[STOCK] Conflicto en %s: se escribió %d y se encontró %d; el cambio ya está aplicado y no se repite\n", itemRef, target, verified)
+			delta := target - current
+			return verified - delta, verified, nil
+		}
+		return current, target, nil
+	}
+
+	return 0, 0, fmt.Errorf("%w: %s after %d attempts", ErrStockConflict, itemRef, s.maxAttempts)
+}
+
+func (s *stockService) currentStock(apiUrl, apiKey string, itemRef domain.ItemRef) (int64, error) {
+	details, err := s.repo.GetItemByIDWithDetails(apiUrl, apiKey, itemRef.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to read stock for %s: %w", itemRef, err)
+	}
+	return int64(details.Availability), nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}